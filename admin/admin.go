@@ -0,0 +1,299 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Zetkolink/oracle/exporter"
+	"github.com/Zetkolink/oracle/leader"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/outbox"
+	"github.com/Zetkolink/oracle/models/whiteList"
+	"github.com/Zetkolink/oracle/pseudonym"
+	"github.com/Zetkolink/oracle/scheduler"
+	"github.com/Zetkolink/oracle/services/pool"
+)
+
+const (
+	defaultAddr     = ":8080"
+	defaultLimit    = 20
+	maxLimit        = 100
+	maxBodySize     = 1 << 20
+	shutdownTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNoToken admin token not configured.
+	ErrNoToken = errors.New("admin token required")
+
+	errNotFound = errors.New("not found")
+	errMethod   = errors.New("method not allowed")
+)
+
+// Server admin HTTP API.
+type Server struct {
+	server    *http.Server
+	mux       *http.ServeMux
+	token     string
+	models    ModelsSet
+	pseudonym *pseudonym.Pseudonym
+	exporter  *exporter.Exporter
+	scheduler *scheduler.Scheduler
+	elector   *leader.Elector
+	pool      *pool.Pool
+	wg        sync.WaitGroup
+}
+
+// Config configuration for Server.
+type Config struct {
+	Addr      string
+	Token     string
+	Models    ModelsSet
+	Pseudonym *pseudonym.Pseudonym
+	Exporter  *exporter.Exporter
+	Scheduler *scheduler.Scheduler
+	Elector   *leader.Elector
+	Pool      *pool.Pool
+}
+
+// ModelsSet models used by Server.
+type ModelsSet struct {
+	GoalTypes goalTypes.Repository
+	Goals     goals.Repository
+	WhiteList whiteList.Repository
+	Outbox    outbox.Repository
+}
+
+// page paginated list response.
+type page struct {
+	Items  interface{} `json:"items"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// errorResponse error response.
+type errorResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// validationError invalid request fields.
+type validationError map[string]string
+
+// NewServer create new instance of Server.
+func NewServer(config Config) (*Server, error) {
+	if config.Token == "" {
+		return nil, ErrNoToken
+	}
+
+	if config.Addr == "" {
+		config.Addr = defaultAddr
+	}
+
+	s := &Server{
+		mux:       http.NewServeMux(),
+		token:     config.Token,
+		models:    config.Models,
+		pseudonym: config.Pseudonym,
+		exporter:  config.Exporter,
+		scheduler: config.Scheduler,
+		elector:   config.Elector,
+		pool:      config.Pool,
+	}
+
+	s.server = &http.Server{
+		Addr:         config.Addr,
+		Handler:      s.mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	s.Handle("/goal_types", s.goalTypes)
+	s.Handle("/goal_types/", s.goalType)
+	s.Handle("/goals", s.goals)
+	s.Handle("/goals/", s.goal)
+	s.Handle("/white_list", s.whiteList)
+	s.Handle("/white_list/", s.whiteListItem)
+	s.Handle("/aliases/rotate", s.rotateAliases)
+	s.Handle("/users/", s.exportUser)
+	s.Handle("/outbox", s.outbox)
+	s.Handle("/outbox/", s.outboxItem)
+	s.Handle("/jobs", s.jobs)
+	s.Handle("/leader", s.leader)
+	s.Handle("/pool", s.poolStats)
+
+	return s, nil
+}
+
+// Handle register authenticated handler for pattern.
+func (s *Server) Handle(pattern string, handler http.HandlerFunc) {
+	s.mux.Handle(pattern, s.auth(handler))
+}
+
+// Listen start serving until ctx is done.
+func (s *Server) Listen(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(2)
+
+	go func() {
+		defer s.wg.Done()
+
+		err := s.server.Serve(listener)
+
+		if err != nil && err != http.ErrServerClosed {
+			log.Println(err)
+		}
+	}()
+
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		err := s.server.Shutdown(shutdownCtx)
+
+		if err != nil {
+			log.Println(err)
+		}
+	}()
+
+	return nil
+}
+
+// Wait wait server to shutdown.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			s.writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// pathID parse ID following prefix in request path.
+func (s *Server) pathID(r *http.Request, prefix string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, prefix), 10, 64)
+
+	if err != nil || id <= 0 {
+		return 0, errNotFound
+	}
+
+	return id, nil
+}
+
+// pagination parse limit and offset query params.
+func (s *Server) pagination(r *http.Request) (int, int, error) {
+	limit, offset := defaultLimit, 0
+	fields := validationError{}
+	query := r.URL.Query()
+
+	if v := query.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+
+		if err != nil || l <= 0 || l > maxLimit {
+			fields["limit"] = "must be between 1 and " + strconv.Itoa(maxLimit)
+		}
+
+		limit = l
+	}
+
+	if v := query.Get("offset"); v != "" {
+		o, err := strconv.Atoi(v)
+
+		if err != nil || o < 0 {
+			fields["offset"] = "must be non-negative"
+		}
+
+		offset = o
+	}
+
+	if len(fields) > 0 {
+		return 0, 0, fields
+	}
+
+	return limit, offset, nil
+}
+
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+
+	if err != nil {
+		return validationError{"body": err.Error()}
+	}
+
+	return nil
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(v)
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// writeError map err to response status.
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	var fields validationError
+
+	switch {
+	case errors.As(err, &fields):
+		s.writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:  "validation failed",
+			Fields: fields,
+		})
+	case err == errNotFound, err == sql.ErrNoRows, err == goalTypes.ErrNotFound,
+		err == goals.ErrNotFound, err == whiteList.ErrNotFound:
+		s.writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+	case err == goalTypes.ErrInUse, err == goals.ErrInUse, err == whiteList.ErrExists:
+		s.writeJSON(w, http.StatusConflict, errorResponse{Error: err.Error()})
+	case err == errMethod:
+		s.writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: err.Error()})
+	default:
+		log.Println(err)
+		s.writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "internal error"})
+	}
+}
+
+func (e validationError) Error() string {
+	fields := make([]string, 0, len(e))
+
+	for field, msg := range e {
+		fields = append(fields, field+": "+msg)
+	}
+
+	return strings.Join(fields, ", ")
+}
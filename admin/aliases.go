@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"net/http"
+)
+
+// rotateRequest aliases rotation request, all users if UserID is 0.
+type rotateRequest struct {
+	UserID int64 `json:"user_id"`
+}
+
+// rotateAliases replace aliases shown to raters with new ones.
+func (s *Server) rotateAliases(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	var req rotateRequest
+
+	err := s.decode(w, r, &req)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	if req.UserID < 0 {
+		s.writeError(w, validationError{"user_id": "must be non-negative"})
+		return
+	}
+
+	rotated, err := s.pseudonym.Rotate(r.Context(), req.UserID)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]int64{
+		"rotated": rotated,
+	})
+}
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Zetkolink/oracle/exporter"
+)
+
+const exportSuffix = "/export"
+
+// contentTypes export formats content types.
+var contentTypes = map[string]string{
+	exporter.FormatCSV:  "text/csv; charset=utf-8",
+	exporter.FormatJSON: "application/json; charset=utf-8",
+}
+
+// exportUser send user history as CSV or JSON file,
+// GET /users/{id}/export?format=csv.
+func (s *Server) exportUser(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, exportSuffix) {
+		s.writeError(w, errNotFound)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSuffix(
+		strings.TrimPrefix(r.URL.Path, "/users/"), exportSuffix), 10, 64)
+
+	if err != nil || id <= 0 {
+		s.writeError(w, errNotFound)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+
+	if format == "" {
+		format = exporter.FormatJSON
+	}
+
+	contentType, ok := contentTypes[format]
+
+	if !ok {
+		s.writeError(w, validationError{"format": "must be csv or json"})
+		return
+	}
+
+	name, data, err := s.exporter.File(r.Context(), id, format)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		log.Println(err)
+	}
+}
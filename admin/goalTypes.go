@@ -0,0 +1,144 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Zetkolink/oracle/models/goalTypes"
+)
+
+// goalTypes list and create goal types.
+func (s *Server) goalTypes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		limit, offset, err := s.pagination(r)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		gTypes, err := s.models.GoalTypes.List(r.Context())
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		res := page{
+			Items:  []*goalTypes.GoalType{},
+			Total:  int64(len(gTypes)),
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if offset < len(gTypes) {
+			gTypes = gTypes[offset:]
+
+			if limit < len(gTypes) {
+				gTypes = gTypes[:limit]
+			}
+
+			res.Items = gTypes
+		}
+
+		s.writeJSON(w, http.StatusOK, res)
+	case http.MethodPost:
+		var gType goalTypes.GoalType
+
+		err := s.decodeGoalType(w, r, &gType)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		gType.ID, err = s.models.GoalTypes.Create(r.Context(), &gType)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusCreated, gType)
+	default:
+		s.writeError(w, errMethod)
+	}
+}
+
+// goalType get, update and delete goal type.
+func (s *Server) goalType(w http.ResponseWriter, r *http.Request) {
+	id, err := s.pathID(r, "/goal_types/")
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		gType, err := s.models.GoalTypes.Get(r.Context(), id)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, gType)
+	case http.MethodPut:
+		var gType goalTypes.GoalType
+
+		err := s.decodeGoalType(w, r, &gType)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		gType.ID = id
+		err = s.models.GoalTypes.Update(r.Context(), &gType)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, gType)
+	case http.MethodDelete:
+		err := s.models.GoalTypes.Delete(r.Context(), id)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, errMethod)
+	}
+}
+
+func (s *Server) decodeGoalType(w http.ResponseWriter, r *http.Request, gType *goalTypes.GoalType) error {
+	err := s.decode(w, r, gType)
+
+	if err != nil {
+		return err
+	}
+
+	gType.Name = strings.TrimSpace(gType.Name)
+	fields := validationError{}
+
+	if gType.Name == "" {
+		fields["name"] = "required"
+	}
+
+	if gType.Points < 0 {
+		fields["points"] = "must be non-negative"
+	}
+
+	if len(fields) > 0 {
+		return fields
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Zetkolink/oracle/models/goals"
+)
+
+// goals list goals of catalog and create goal.
+func (s *Server) goals(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		limit, offset, err := s.pagination(r)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		var gType int64
+
+		if v := r.URL.Query().Get("type"); v != "" {
+			gType, err = strconv.ParseInt(v, 10, 64)
+
+			if err != nil || gType <= 0 {
+				s.writeError(w, validationError{"type": "must be positive integer"})
+				return
+			}
+		}
+
+		total, err := s.models.Goals.Count(r.Context(), gType)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		list, err := s.models.Goals.Page(r.Context(), gType, limit, offset)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, page{
+			Items:  list,
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		})
+	case http.MethodPost:
+		var goal goals.Goal
+
+		err := s.decodeGoal(w, r, &goal)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		goal.ID, err = s.models.Goals.Create(r.Context(), &goal)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusCreated, goal)
+	default:
+		s.writeError(w, errMethod)
+	}
+}
+
+// goal get, update and delete goal.
+func (s *Server) goal(w http.ResponseWriter, r *http.Request) {
+	id, err := s.pathID(r, "/goals/")
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		goal, err := s.models.Goals.Get(r.Context(), id)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, goal)
+	case http.MethodPut:
+		var goal goals.Goal
+
+		err := s.decodeGoal(w, r, &goal)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		goal.ID = id
+		err = s.models.Goals.Update(r.Context(), &goal)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, goal)
+	case http.MethodDelete:
+		err := s.models.Goals.Delete(r.Context(), id)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, errMethod)
+	}
+}
+
+func (s *Server) decodeGoal(w http.ResponseWriter, r *http.Request, goal *goals.Goal) error {
+	err := s.decode(w, r, goal)
+
+	if err != nil {
+		return err
+	}
+
+	goal.Description = strings.TrimSpace(goal.Description)
+	fields := validationError{}
+
+	if goal.Description == "" {
+		fields["description"] = "required"
+	}
+
+	if goal.Type <= 0 {
+		fields["type"] = "required"
+	} else {
+		_, err = s.models.GoalTypes.Get(r.Context(), goal.Type)
+
+		if err == sql.ErrNoRows {
+			fields["type"] = "goal type not found"
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(fields) > 0 {
+		return fields
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Zetkolink/oracle/scheduler"
+)
+
+// jobs list scheduled jobs with run history, GET /jobs.
+func (s *Server) jobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	statuses := []scheduler.Status{}
+
+	if s.scheduler != nil {
+		statuses = s.scheduler.Statuses()
+	}
+
+	s.writeJSON(w, http.StatusOK, statuses)
+}
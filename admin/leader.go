@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"net/http"
+)
+
+// leader get instance leader election status, GET /leader.
+func (s *Server) leader(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	if s.elector == nil {
+		s.writeError(w, errNotFound)
+		return
+	}
+
+	status, err := s.elector.Status(r.Context())
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zetkolink/oracle/models/outbox"
+)
+
+// outboxMessage outbox message with delivery log.
+type outboxMessage struct {
+	*outbox.Message
+	Log []*outbox.Attempt `json:"log"`
+}
+
+// outbox list notifications outbox filtered by status and user,
+// GET /outbox?status=failed&user_id=1.
+func (s *Server) outbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	limit, offset, err := s.pagination(r)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	filter, err := s.outboxFilter(r)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	total, err := s.models.Outbox.Count(r.Context(), filter)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	items, err := s.models.Outbox.List(r.Context(), filter, limit, offset)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, page{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// outboxItem get outbox message with delivery log.
+func (s *Server) outboxItem(w http.ResponseWriter, r *http.Request) {
+	id, err := s.pathID(r, "/outbox/")
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	msg, err := s.models.Outbox.Get(r.Context(), id)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	attempts, err := s.models.Outbox.ListAttempts(r.Context(), id)
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, outboxMessage{
+		Message: msg,
+		Log:     attempts,
+	})
+}
+
+func (s *Server) outboxFilter(r *http.Request) (outbox.Filter, error) {
+	var filter outbox.Filter
+
+	fields := validationError{}
+	query := r.URL.Query()
+
+	switch status := query.Get("status"); status {
+	case "", outbox.StatusPending, outbox.StatusSent, outbox.StatusFailed:
+		filter.Status = status
+	default:
+		fields["status"] = "must be pending, sent or failed"
+	}
+
+	if v := query.Get("user_id"); v != "" {
+		userID, err := strconv.ParseInt(v, 10, 64)
+
+		if err != nil || userID <= 0 {
+			fields["user_id"] = "must be positive"
+		}
+
+		filter.UserID = userID
+	}
+
+	if len(fields) > 0 {
+		return filter, fields
+	}
+
+	return filter, nil
+}
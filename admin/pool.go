@@ -0,0 +1,20 @@
+package admin
+
+import (
+	"net/http"
+)
+
+// poolStats get workers pool back-pressure metrics, GET /pool.
+func (s *Server) poolStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, errMethod)
+		return
+	}
+
+	if s.pool == nil {
+		s.writeError(w, errNotFound)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, s.pool.Stats())
+}
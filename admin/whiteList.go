@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Zetkolink/oracle/models/whiteList"
+)
+
+// whiteList list white list and add user to it.
+func (s *Server) whiteList(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		limit, offset, err := s.pagination(r)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		total, err := s.models.WhiteList.Count(r.Context())
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		items, err := s.models.WhiteList.List(r.Context(), limit, offset)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, page{
+			Items:  items,
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		})
+	case http.MethodPost:
+		var item whiteList.Item
+
+		err := s.decode(w, r, &item)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		if item.UserID <= 0 {
+			s.writeError(w, validationError{"user_id": "required"})
+			return
+		}
+
+		err = s.models.WhiteList.Create(r.Context(), &item)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		s.writeJSON(w, http.StatusCreated, item)
+	default:
+		s.writeError(w, errMethod)
+	}
+}
+
+// whiteListItem check and delete white list user.
+func (s *Server) whiteListItem(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.pathID(r, "/white_list/")
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		ok, err := s.models.WhiteList.Check(r.Context(), userID)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		if !ok {
+			s.writeError(w, errNotFound)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, whiteList.Item{UserID: userID})
+	case http.MethodDelete:
+		err := s.models.WhiteList.Delete(r.Context(), userID)
+
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, errMethod)
+	}
+}
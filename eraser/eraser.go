@@ -0,0 +1,122 @@
+package eraser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Zetkolink/oracle/models/aliases"
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/forRate"
+	"github.com/Zetkolink/oracle/models/reminders"
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/streaks"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/go-redis/redis/v8"
+)
+
+// stateKeys redis keys of screen states and user cache.
+var stateKeys = []string{"user_%d", "tasks_%d", "appeal_%d", "settings_%d"}
+
+// Eraser delete user account with personal data.
+type Eraser struct {
+	models      ModelsSet
+	redisClient *redis.Client
+}
+
+type Config struct {
+	Models      ModelsSet
+	RedisClient *redis.Client
+}
+
+type ModelsSet struct {
+	Users       users.Repository
+	UserGoals   userGoals.Repository
+	Evaluations evaluations.Repository
+	ForRate     forRate.Repository
+	Aliases     aliases.Repository
+	Streaks     streaks.Repository
+	Scores      scores.Repository
+	Reminders   reminders.Repository
+}
+
+func NewEraser(config Config) *Eraser {
+	return &Eraser{
+		models:      config.Models,
+		redisClient: config.RedisClient,
+	}
+}
+
+// Erase delete user and everything linked to user, only anonymous
+// score aggregates are kept. Reviews, proofs and evaluations of user
+// goals are deleted with goals. Safe to call again after failure.
+func (e *Eraser) Erase(ctx context.Context, userID int64) error {
+	err := e.models.Streaks.DeleteByUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.models.Reminders.DeleteByUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.models.Aliases.DeleteByUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.models.ForRate.DeleteByUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.models.Evaluations.DeleteByUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.models.Scores.Erase(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	// Goals typed by user are found by user goals and templates,
+	// so they are deleted together.
+	err = e.models.UserGoals.Erase(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.models.Users.Delete(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return e.purge(ctx, userID)
+}
+
+// purge delete user redis keys: cache and screen states.
+func (e *Eraser) purge(ctx context.Context, userID int64) error {
+	keys := make([]string, 0, len(stateKeys))
+
+	for _, key := range stateKeys {
+		keys = append(keys, fmt.Sprintf(key, userID))
+	}
+
+	err := e.redisClient.Del(ctx, keys...).Err()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
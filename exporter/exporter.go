@@ -0,0 +1,305 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+// Export formats.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// CSV record kinds.
+const (
+	recordGoal     = "goal"
+	recordGiven    = "evaluation_given"
+	recordReceived = "evaluation_received"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	// ErrFormat export format not supported.
+	ErrFormat = errors.New("unknown export format")
+
+	csvHeader = []string{"record", "user_goal_id", "date", "type", "goal",
+		"phase", "status", "from", "to", "approved"}
+)
+
+// Exporter export user history.
+type Exporter struct {
+	models ModelsSet
+}
+
+type Config struct {
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	Users       users.Repository
+	GoalTypes   goalTypes.Repository
+	Goals       goals.Repository
+	UserGoals   userGoals.Repository
+	Evaluations evaluations.Repository
+}
+
+// Export user goals with evaluations given by user and received
+// from other users, other users are not identified.
+type Export struct {
+	UserID     int64         `json:"user_id"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Goals      []*Goal       `json:"goals"`
+	Given      []*Evaluation `json:"evaluations_given"`
+	Received   []*Evaluation `json:"evaluations_received"`
+}
+
+// Goal user goal joined with goal and goal type.
+type Goal struct {
+	UserGoalID int64     `json:"user_goal_id"`
+	Date       string    `json:"date"`
+	Type       string    `json:"type"`
+	Goal       string    `json:"goal"`
+	Phase      string    `json:"phase"`
+	Status     string    `json:"status"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+}
+
+// Evaluation evaluation of user goal.
+type Evaluation struct {
+	UserGoalID int64  `json:"user_goal_id"`
+	Date       string `json:"date"`
+	Type       string `json:"type"`
+	Goal       string `json:"goal"`
+	Approved   bool   `json:"approved"`
+}
+
+func NewExporter(config Config) *Exporter {
+	return &Exporter{models: config.Models}
+}
+
+// File export user history in format and return file name and content.
+func (e *Exporter) File(ctx context.Context, userID int64, format string) (string, []byte, error) {
+	if format != FormatCSV && format != FormatJSON {
+		return "", nil, ErrFormat
+	}
+
+	export, err := e.Export(ctx, userID)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	var data []byte
+
+	if format == FormatJSON {
+		data, err = json.MarshalIndent(export, "", "  ")
+	} else {
+		data, err = export.CSV()
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := fmt.Sprintf("oracle_%d_%s.%s", userID,
+		export.ExportedAt.Format(dateLayout), format)
+
+	return name, data, nil
+}
+
+// Export collect user history.
+func (e *Exporter) Export(ctx context.Context, userID int64) (*Export, error) {
+	user, err := e.models.Users.Get(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	types, err := e.models.GoalTypes.List(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typeNames := make(map[int64]string)
+
+	for _, gType := range types {
+		typeNames[gType.ID] = gType.Name
+	}
+
+	descriptions := make(map[int64]string)
+
+	describe := func(goalID int64) (string, error) {
+		if description, ok := descriptions[goalID]; ok {
+			return description, nil
+		}
+
+		goal, err := e.models.Goals.Get(ctx, goalID)
+
+		if err != nil {
+			return "", err
+		}
+
+		descriptions[goalID] = goal.Description
+
+		return goal.Description, nil
+	}
+
+	export := &Export{
+		UserID:     user.ID,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	uGoals, err := e.models.UserGoals.ListByUser(ctx, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uGoal := range uGoals {
+		description, err := describe(uGoal.GoalID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		uDate, err := user.Date(uGoal.From)
+
+		if err != nil {
+			return nil, err
+		}
+
+		export.Goals = append(export.Goals, &Goal{
+			UserGoalID: uGoal.ID,
+			Date:       uDate.Format(dateLayout),
+			Type:       typeNames[uGoal.Type],
+			Goal:       description,
+			Phase:      uGoal.Phase,
+			Status:     uGoal.Status,
+			From:       uGoal.From,
+			To:         uGoal.To,
+		})
+
+		received, err := e.models.Evaluations.ListByUserGoal(ctx, uGoal.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, eval := range received {
+			export.Received = append(export.Received, &Evaluation{
+				UserGoalID: uGoal.ID,
+				Date:       uDate.Format(dateLayout),
+				Type:       typeNames[uGoal.Type],
+				Goal:       description,
+				Approved:   eval.Evaluation,
+			})
+		}
+	}
+
+	given, err := e.models.Evaluations.List(ctx, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, eval := range given {
+		uGoal, err := e.models.UserGoals.Get(ctx, eval.UserGoalID)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+
+			return nil, err
+		}
+
+		description, err := describe(uGoal.GoalID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		uDate, err := user.Date(uGoal.From)
+
+		if err != nil {
+			return nil, err
+		}
+
+		export.Given = append(export.Given, &Evaluation{
+			UserGoalID: uGoal.ID,
+			Date:       uDate.Format(dateLayout),
+			Type:       typeNames[uGoal.Type],
+			Goal:       description,
+			Approved:   eval.Evaluation,
+		})
+	}
+
+	return export, nil
+}
+
+// CSV encode export as single table, record column tells
+// goals and evaluations apart.
+func (e *Export) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	err := writer.Write(csvHeader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, goal := range e.Goals {
+		err = writer.Write([]string{recordGoal, strconv.FormatInt(goal.UserGoalID, 10),
+			goal.Date, goal.Type, goal.Goal, goal.Phase, goal.Status,
+			goal.From.Format(time.RFC3339), goal.To.Format(time.RFC3339), ""})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, records := range []struct {
+		kind  string
+		evals []*Evaluation
+	}{{recordGiven, e.Given}, {recordReceived, e.Received}} {
+		for _, eval := range records.evals {
+			err = writer.Write([]string{records.kind, strconv.FormatInt(eval.UserGoalID, 10),
+				eval.Date, eval.Type, eval.Goal, "", "", "", "",
+				strconv.FormatBool(eval.Approved)})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+
+	if writer.Error() != nil {
+		return nil, writer.Error()
+	}
+
+	return buf.Bytes(), nil
+}
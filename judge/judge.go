@@ -0,0 +1,437 @@
+package judge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/forRate"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/proofs"
+	"github.com/Zetkolink/oracle/models/reviews"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+	"github.com/Zetkolink/oracle/scorer"
+	"github.com/Zetkolink/oracle/streaker"
+)
+
+const (
+	// defaultRaters number of users asked to rate each evaluated goal.
+	defaultRaters = 3
+
+	// defaultQuorum number of evaluations enough for verdict.
+	defaultQuorum = 3
+
+	// defaultDeadline time given to raters before verdict.
+	defaultDeadline = 48 * time.Hour
+
+	// maxRound last review round, owner appeals only once.
+	maxRound = 2
+)
+
+var (
+	// ErrNoAppeal user goal can't be appealed.
+	ErrNoAppeal = errors.New("appeal not allowed")
+)
+
+// Judge peer review of evaluated goals.
+type Judge struct {
+	models      ModelsSet
+	scorer      *scorer.Scorer
+	streaker    *streaker.Streaker
+	notificator *notificator.Notificator
+	raters      int
+	quorum      int64
+	deadline    time.Duration
+	rand        *rand.Rand
+	mu          sync.Mutex
+}
+
+type Config struct {
+	Models      ModelsSet
+	Scorer      *scorer.Scorer
+	Streaker    *streaker.Streaker
+	Notificator *notificator.Notificator
+	Raters      int
+	Quorum      int64
+	Deadline    time.Duration
+}
+
+type ModelsSet struct {
+	Users       users.Repository
+	GoalTypes   goalTypes.Repository
+	Goals       goals.Repository
+	UserGoals   userGoals.Repository
+	Evaluations evaluations.Repository
+	ForRate     forRate.Repository
+	Reviews     reviews.Repository
+	Proofs      proofs.Repository
+}
+
+func NewJudge(config Config) *Judge {
+	if config.Raters <= 0 {
+		config.Raters = defaultRaters
+	}
+
+	if config.Quorum <= 0 {
+		config.Quorum = defaultQuorum
+	}
+
+	if config.Deadline <= 0 {
+		config.Deadline = defaultDeadline
+	}
+
+	return &Judge{
+		models:      config.Models,
+		scorer:      config.Scorer,
+		streaker:    config.Streaker,
+		notificator: config.Notificator,
+		raters:      config.Raters,
+		quorum:      config.Quorum,
+		deadline:    config.Deadline,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Open start review of complete user goal if its type is evaluated.
+// Safe to call again after failure, review is created once.
+func (j *Judge) Open(ctx context.Context, uGoal *userGoals.UserGoal) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	gType, err := j.models.GoalTypes.Get(ctx, uGoal.Type)
+
+	if err != nil {
+		return err
+	}
+
+	if !gType.Evaluated {
+		return nil
+	}
+
+	err = j.models.Reviews.Create(ctx, &reviews.Review{
+		UserGoalID: uGoal.ID,
+		Status:     reviews.StatusPending,
+		Round:      1,
+		Deadline:   time.Now().UTC().Add(j.deadline),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return j.enqueue(ctx, uGoal)
+}
+
+// Check decide review of user goal if quorum is collected.
+func (j *Judge) Check(ctx context.Context, uGoalID int64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	review, err := j.models.Reviews.Get(ctx, uGoalID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	if review.Status != reviews.StatusPending {
+		return nil
+	}
+
+	count, err := j.models.Evaluations.Count(ctx, uGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	if count < j.quorum {
+		return nil
+	}
+
+	return j.decide(ctx, review)
+}
+
+// Run decide pending reviews with collected quorum or passed deadline.
+func (j *Judge) Run(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	list, err := j.models.Reviews.ListByStatus(ctx, reviews.StatusPending)
+
+	if err != nil {
+		return err
+	}
+
+	for _, review := range list {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if time.Now().UTC().Before(review.Deadline) {
+			count, err := j.models.Evaluations.Count(ctx, review.UserGoalID)
+
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			if count < j.quorum {
+				continue
+			}
+		}
+
+		err = j.decide(ctx, review)
+
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	return nil
+}
+
+// Appeal reopen rejected review with owner proof.
+// Allowed once per user goal.
+func (j *Judge) Appeal(ctx context.Context, userID int64, uGoalID int64, proof *proofs.Proof) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	uGoal, err := j.models.UserGoals.Get(ctx, uGoalID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNoAppeal
+		}
+
+		return err
+	}
+
+	if uGoal.UserID != userID {
+		return ErrNoAppeal
+	}
+
+	review, err := j.models.Reviews.Get(ctx, uGoalID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNoAppeal
+		}
+
+		return err
+	}
+
+	if review.Status != reviews.StatusRejected || review.Round >= maxRound {
+		return ErrNoAppeal
+	}
+
+	proof.UserGoalID = uGoalID
+	proof.Kind = proofs.KindAppeal
+	err = j.models.Proofs.Create(ctx, proof)
+
+	if err != nil {
+		return err
+	}
+
+	err = j.models.Evaluations.DeleteByUserGoal(ctx, uGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	err = j.models.UserGoals.UpdateStatus(ctx, uGoalID, userGoals.StatusComplete)
+
+	if err != nil {
+		return err
+	}
+
+	review.Status = reviews.StatusPending
+	review.Round++
+	review.Deadline = time.Now().UTC().Add(j.deadline)
+	review.DecidedAt = nil
+
+	err = j.models.Reviews.Update(ctx, review)
+
+	if err != nil {
+		return err
+	}
+
+	return j.enqueue(ctx, uGoal)
+}
+
+// CanAppeal check user goal review is rejected and not appealed yet.
+func (j *Judge) CanAppeal(ctx context.Context, uGoalID int64) (bool, error) {
+	review, err := j.models.Reviews.Get(ctx, uGoalID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return review.Status == reviews.StatusRejected && review.Round < maxRound, nil
+}
+
+// decide close review by evaluations result, fail rejected goal
+// and notify owner.
+func (j *Judge) decide(ctx context.Context, review *reviews.Review) error {
+	approved, err := j.models.Evaluations.GetResult(ctx, review.UserGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	uGoal, err := j.models.UserGoals.Get(ctx, review.UserGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	err = j.models.ForRate.DeleteByUserGoal(ctx, uGoal.ID)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	review.DecidedAt = &now
+	review.Status = reviews.StatusApproved
+
+	if !approved {
+		review.Status = reviews.StatusRejected
+
+		err = j.models.UserGoals.UpdateStatus(ctx, uGoal.ID, userGoals.StatusFailed)
+
+		if err != nil {
+			return err
+		}
+
+		uGoal.Status = userGoals.StatusFailed
+	}
+
+	err = j.models.Reviews.Update(ctx, review)
+
+	if err != nil {
+		return err
+	}
+
+	err = j.scorer.Score(ctx, uGoal)
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	err = j.streaker.Track(ctx, uGoal)
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	if approved && review.Round == 1 {
+		return nil
+	}
+
+	return j.notify(ctx, uGoal, review)
+}
+
+func (j *Judge) notify(ctx context.Context, uGoal *userGoals.UserGoal, review *reviews.Review) error {
+	goal, err := j.models.Goals.Get(ctx, uGoal.GoalID)
+
+	if err != nil {
+		return err
+	}
+
+	user, err := j.models.Users.Get(ctx, uGoal.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	uDate, err := user.Date(uGoal.From)
+
+	if err != nil {
+		return err
+	}
+
+	title := "Ваша задача не прошла проверку"
+
+	if review.Status == reviews.StatusApproved {
+		title = "Обжалование принято, задача засчитана"
+	}
+
+	text := fmt.Sprintf("%s\nДата\n ⏱ - %s\nЗадача\n 💡 - %s",
+		title, uDate.Format("January 2"), goal.Description)
+
+	params := map[string]interface{}{
+		"user_goal": uGoal.ID,
+		"appeal":    review.Status == reviews.StatusRejected && review.Round < maxRound,
+	}
+
+	if params["appeal"] == true {
+		text += "\n\nВы можете один раз обжаловать решение, отправив фото или описание выполнения"
+	}
+
+	err = j.notificator.SendParams(user, "verdict", text, params)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// enqueue put user goal to rate queues of random
+// active users except the owner.
+func (j *Judge) enqueue(ctx context.Context, uGoal *userGoals.UserGoal) error {
+	usrs, err := j.models.Users.List(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	candidates := make([]*users.User, 0, len(usrs))
+
+	for _, user := range usrs {
+		if user.Active && user.ID != uGoal.UserID {
+			candidates = append(candidates, user)
+		}
+	}
+
+	j.rand.Shuffle(len(candidates), func(i, k int) {
+		candidates[i], candidates[k] = candidates[k], candidates[i]
+	})
+
+	if len(candidates) > j.raters {
+		candidates = candidates[:j.raters]
+	}
+
+	for _, user := range candidates {
+		err = j.models.ForRate.Create(ctx, &forRate.ForRate{
+			UserID:     user.ID,
+			UserGoalID: uGoal.ID,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	defaultKey = "leader"
+	defaultTTL = 15 * time.Second
+
+	// historySize leadership changes kept in status.
+	historySize = 20
+
+	releaseTimeout = 5 * time.Second
+)
+
+var (
+	// acquireScript take lease if it is free or renew own lease.
+	acquireScript = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+
+if holder == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+
+return 0`)
+
+	// releaseScript delete lease if it is own.
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+
+return 0`)
+)
+
+// Elector elect single leader among instances by Redis lease.
+// Leader renews lease every third of TTL, followers try to take
+// it over, so leadership moves in TTL after leader is gone.
+// Failed renewals are tolerated while lease is valid, leader
+// work is cancelled a quarter of TTL before lease expires.
+type Elector struct {
+	redisClient *redis.Client
+	key         string
+	id          string
+	ttl         time.Duration
+	mu          sync.RWMutex
+	leader      bool
+	since       *time.Time
+	changes     []Change
+}
+
+// Config configuration for Elector.
+type Config struct {
+	RedisClient *redis.Client
+	// Key lease key, instances with same key compete.
+	Key string
+	// ID instance ID, host name and PID by default.
+	ID  string
+	TTL time.Duration
+}
+
+// Status instance election state.
+type Status struct {
+	ID       string     `json:"id"`
+	Leader   bool       `json:"leader"`
+	LeaderID string     `json:"leader_id"`
+	Since    *time.Time `json:"since"`
+	Changes  []Change   `json:"changes"`
+}
+
+// Change leadership change of instance.
+type Change struct {
+	Leader bool      `json:"leader"`
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason"`
+}
+
+// NewElector create new instance of Elector.
+func NewElector(config Config) *Elector {
+	if config.Key == "" {
+		config.Key = defaultKey
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+
+	if config.ID == "" {
+		host, _ := os.Hostname()
+		config.ID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+
+	return &Elector{
+		redisClient: config.RedisClient,
+		key:         config.Key,
+		id:          config.ID,
+		ttl:         config.TTL,
+	}
+}
+
+// Run campaign until ctx is done. While instance is leader lead
+// is run with context which is cancelled when leadership is lost,
+// lead should return after its work is stopped. Lease is released
+// when lead returns error, so other instance can take over.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context) error) {
+	var t *term
+
+	for {
+		attempt := time.Now()
+		held, err := e.campaign(ctx)
+
+		if err != nil && ctx.Err() == nil {
+			log.Println(err)
+		}
+
+		// Lease is valid for TTL since attempt, failed renewal is
+		// retried until fence cancels leader work.
+		switch {
+		case held && t == nil:
+			e.change(true, "lease acquired")
+			t = start(ctx, lead, e.fence(attempt))
+		case held:
+			t.extend(e.fence(attempt))
+		case t != nil && err == nil && ctx.Err() == nil:
+			t.stop()
+			t = nil
+			e.step("lease taken over")
+		}
+
+		// done is nil while follower, so it blocks.
+		var done <-chan error
+
+		if t != nil {
+			done = t.done
+		}
+
+		select {
+		case <-ctx.Done():
+			if t != nil {
+				t.stop()
+				e.step("shutdown")
+			}
+
+			return
+		case err = <-done:
+			reason := "leader stopped"
+
+			if t.expired() {
+				reason = "lease expired"
+			}
+
+			t.close()
+			t = nil
+
+			if err != nil {
+				log.Println(err)
+			}
+
+			e.step(reason)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(e.ttl):
+			}
+		case <-time.After(e.ttl / 3):
+		}
+	}
+}
+
+// IsLeader check instance is leader.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.leader
+}
+
+// Status get instance election state and current lease holder.
+func (e *Elector) Status(ctx context.Context) (*Status, error) {
+	holder, err := e.redisClient.Get(ctx, e.key).Result()
+
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return &Status{
+		ID:       e.id,
+		Leader:   e.leader,
+		LeaderID: holder,
+		Since:    e.since,
+		Changes:  append([]Change(nil), e.changes...),
+	}, nil
+}
+
+func (e *Elector) campaign(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.ttl/3)
+	defer cancel()
+
+	held, err := acquireScript.Run(ctx, e.redisClient, []string{e.key},
+		e.id, e.ttl.Milliseconds()).Int()
+
+	if err != nil {
+		return false, err
+	}
+
+	return held == 1, nil
+}
+
+// fence get time until leader work must be stopped
+// for lease acquired at attempt.
+func (e *Elector) fence(attempt time.Time) time.Duration {
+	return time.Until(attempt.Add(e.ttl - e.ttl/4))
+}
+
+// step release lease and become follower.
+func (e *Elector) step(reason string) {
+	e.release()
+	e.change(false, reason)
+}
+
+func (e *Elector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	err := releaseScript.Run(ctx, e.redisClient, []string{e.key}, e.id).Err()
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (e *Elector) change(leader bool, reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	e.leader = leader
+	e.since = &now
+	e.changes = append(e.changes, Change{
+		Leader: leader,
+		At:     now,
+		Reason: reason,
+	})
+
+	if len(e.changes) > historySize {
+		e.changes = e.changes[len(e.changes)-historySize:]
+	}
+
+	if leader {
+		log.Printf("instance %s became leader: %s", e.id, reason)
+	} else {
+		log.Printf("instance %s is follower: %s", e.id, reason)
+	}
+}
+
+// term leadership term.
+type term struct {
+	cancel context.CancelFunc
+	fence  *time.Timer
+	fenced int32
+	done   chan error
+}
+
+// start run lead until it returns, ctx is done or fence passes.
+func start(ctx context.Context, lead func(ctx context.Context) error, fence time.Duration) *term {
+	leadCtx, cancel := context.WithCancel(ctx)
+
+	t := &term{
+		cancel: cancel,
+		done:   make(chan error, 1),
+	}
+
+	t.fence = time.AfterFunc(fence, func() {
+		atomic.StoreInt32(&t.fenced, 1)
+		cancel()
+	})
+
+	go func() {
+		t.done <- lead(leadCtx)
+	}()
+
+	return t
+}
+
+// extend move fence after lease is renewed. Lead
+// cancelled by fence is not resumed.
+func (t *term) extend(fence time.Duration) {
+	if t.fence.Stop() {
+		t.fence.Reset(fence)
+	}
+}
+
+// expired check lead was cancelled by fence.
+func (t *term) expired() bool {
+	return atomic.LoadInt32(&t.fenced) == 1
+}
+
+// close cancel term after lead returned.
+func (t *term) close() {
+	t.fence.Stop()
+	t.cancel()
+}
+
+// stop cancel term and wait lead to return.
+func (t *term) stop() {
+	t.close()
+
+	err := <-t.done
+
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,281 @@
+package leader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisStub minimal Redis server which answers lease scripts,
+// failing makes every script call fail like unreachable Redis.
+type redisStub struct {
+	mu      sync.Mutex
+	failing bool
+	held    int
+}
+
+func newRedisStub(t *testing.T) (*redisStub, *redis.Client) {
+	stub := &redisStub{held: 1}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go stub.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:       listener.Addr().String(),
+		MaxRetries: -1,
+	})
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return stub, client
+}
+
+func (s *redisStub) set(failing bool, held int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failing = failing
+	s.held = held
+}
+
+func (s *redisStub) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(r)
+
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		failing, held := s.failing, s.held
+		s.mu.Unlock()
+
+		reply := "+OK\r\n"
+
+		switch strings.ToUpper(args[0]) {
+		case "EVALSHA", "EVAL":
+			reply = fmt.Sprintf(":%d\r\n", held)
+
+			if failing {
+				reply = "-ERR unavailable\r\n"
+			}
+		}
+
+		_, err = io.WriteString(conn, reply)
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readCommand read RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+
+	for i := range args {
+		line, err = r.ReadString('\n')
+
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+
+		_, err = io.ReadFull(r, buf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		args[i] = string(buf[:size])
+	}
+
+	return args, nil
+}
+
+// leadTest lead which reports start and cancel of its work.
+type leadTest struct {
+	started   chan time.Time
+	cancelled chan time.Time
+}
+
+func newLeadTest() *leadTest {
+	return &leadTest{
+		started:   make(chan time.Time, 10),
+		cancelled: make(chan time.Time, 10),
+	}
+}
+
+func (l *leadTest) lead(ctx context.Context) error {
+	l.started <- time.Now()
+	<-ctx.Done()
+	l.cancelled <- time.Now()
+
+	return nil
+}
+
+func runElector(t *testing.T, e *Elector, l *leadTest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		e.Run(ctx, l.lead)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func wait(t *testing.T, ch chan time.Time, what string) time.Time {
+	select {
+	case at := <-ch:
+		return at
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s timed out", what)
+	}
+
+	return time.Time{}
+}
+
+func waitFollower(t *testing.T, e *Elector) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for e.IsLeader() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if e.IsLeader() {
+		t.Fatal("instance is still leader")
+	}
+}
+
+// Leader keeps working through renewal failures while lease is valid.
+func TestRunToleratesRenewalFailure(t *testing.T) {
+	stub, client := newRedisStub(t)
+	ttl := 600 * time.Millisecond
+	e := NewElector(Config{RedisClient: client, ID: "a", TTL: ttl})
+	l := newLeadTest()
+
+	runElector(t, e, l)
+	wait(t, l.started, "lead start")
+
+	// Fail one renewal, next one succeeds before fence.
+	stub.set(true, 1)
+	time.Sleep(ttl / 3)
+	stub.set(false, 1)
+	time.Sleep(ttl)
+
+	select {
+	case <-l.cancelled:
+		t.Fatal("lead cancelled after single renewal failure")
+	default:
+	}
+
+	if !e.IsLeader() {
+		t.Error("instance stepped down")
+	}
+}
+
+// Leader work is cancelled before lease expires when Redis is gone.
+func TestRunFencesBeforeExpiry(t *testing.T) {
+	stub, client := newRedisStub(t)
+	ttl := 600 * time.Millisecond
+	e := NewElector(Config{RedisClient: client, ID: "a", TTL: ttl})
+	l := newLeadTest()
+
+	runElector(t, e, l)
+	wait(t, l.started, "lead start")
+
+	stub.set(true, 1)
+	failed := time.Now()
+	cancelled := wait(t, l.cancelled, "lead cancel")
+
+	// Last successful renewal was before failure, so lease
+	// expires at most TTL after it.
+	if cancelled.After(failed.Add(ttl)) {
+		t.Errorf("lead cancelled %s after renewals failed, lease TTL is %s",
+			cancelled.Sub(failed), ttl)
+	}
+
+	waitFollower(t, e)
+}
+
+func TestRunTakenOver(t *testing.T) {
+	stub, client := newRedisStub(t)
+	e := NewElector(Config{RedisClient: client, ID: "a", TTL: 300 * time.Millisecond})
+	l := newLeadTest()
+
+	runElector(t, e, l)
+	wait(t, l.started, "lead start")
+
+	stub.set(false, 0)
+	wait(t, l.cancelled, "lead cancel")
+	waitFollower(t, e)
+
+	status, err := e.Status(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := status.Changes[len(status.Changes)-1]
+
+	if status.Leader || last.Reason != "lease taken over" {
+		t.Errorf("status = %+v, want follower after take over", status)
+	}
+}
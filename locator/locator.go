@@ -0,0 +1,135 @@
+package locator
+
+import (
+	"context"
+	"errors"
+
+	"googlemaps.github.io/maps"
+)
+
+const (
+	language = "ru"
+	locality = "locality"
+)
+
+var (
+	// ErrNotFound place not found.
+	ErrNotFound = errors.New("place not found")
+)
+
+// Locator resolve user city and timezone.
+type Locator struct {
+	mapsClient *maps.Client
+}
+
+type Config struct {
+	MapsClient *maps.Client
+}
+
+// Place user city with timezone.
+type Place struct {
+	City     string
+	Timezone string
+}
+
+func NewLocator(config Config) *Locator {
+	return &Locator{
+		mapsClient: config.MapsClient,
+	}
+}
+
+// City find city by name and get its timezone.
+func (l *Locator) City(ctx context.Context, city string) (*Place, error) {
+	resp, err := l.mapsClient.Geocode(ctx, &maps.GeocodingRequest{
+		Address:  city,
+		Language: language,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, ErrNotFound
+	}
+
+	timezone, err := l.timezone(ctx, &resp[0].Geometry.Location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	place := &Place{
+		City:     l.locality(&resp[0]),
+		Timezone: timezone,
+	}
+
+	if place.City == "" {
+		place.City = city
+	}
+
+	return place, nil
+}
+
+// Coordinates find city by coordinates and get its timezone.
+// City is empty when coordinates are out of any city.
+func (l *Locator) Coordinates(ctx context.Context, lat float64, lng float64) (*Place, error) {
+	location := &maps.LatLng{
+		Lat: lat,
+		Lng: lng,
+	}
+
+	timezone, err := l.timezone(ctx, location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := l.mapsClient.ReverseGeocode(ctx, &maps.GeocodingRequest{
+		LatLng:     location,
+		ResultType: []string{locality},
+		Language:   language,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	place := &Place{
+		Timezone: timezone,
+	}
+
+	if len(resp) > 0 {
+		place.City = l.locality(&resp[0])
+	}
+
+	return place, nil
+}
+
+func (l *Locator) timezone(ctx context.Context, location *maps.LatLng) (string, error) {
+	timezone, err := l.mapsClient.Timezone(ctx, &maps.TimezoneRequest{
+		Location: location,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if timezone.TimeZoneID == "" {
+		return "", ErrNotFound
+	}
+
+	return timezone.TimeZoneID, nil
+}
+
+func (l *Locator) locality(result *maps.GeocodingResult) string {
+	for _, component := range result.AddressComponents {
+		for _, t := range component.Types {
+			if t == locality {
+				return component.LongName
+			}
+		}
+	}
+
+	return ""
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"io/ioutil"
 	"log"
 	"os"
@@ -33,16 +34,27 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(os.Args[2:])
 
-	err = initOracle()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	err := initOracle()
 
 	if err != nil {
 		log.Fatal(err)
 	}
-}
 
-func main() {
-	err := a.Run()
+	ctx, cancel := context.WithCancel(context.Background())
+	err = a.Run(ctx)
 
 	if err != nil {
 		log.Fatal(err)
@@ -50,10 +62,10 @@ func main() {
 
 	log.Println("Success start")
 
-	listenSignals()
+	listenSignals(cancel)
 }
 
-func listenSignals() {
+func listenSignals(cancel context.CancelFunc) {
 	signals := make(chan os.Signal, 1)
 
 	signal.Notify(signals,
@@ -64,7 +76,12 @@ func listenSignals() {
 	for sig := range signals {
 		log.Println("Got signal: " + sig.String())
 
-		_ = destroyOracle()
+		cancel()
+		err := destroyOracle()
+
+		if err != nil {
+			log.Println(err)
+		}
 
 		return
 	}
@@ -87,8 +104,12 @@ func initOracle() error {
 
 func destroyOracle() error {
 	if a != nil {
-		a.Stop()
+		err := a.Stop()
 		a = nil
+
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
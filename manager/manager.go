@@ -3,35 +3,48 @@ package manager
 import (
 	"context"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/Zetkolink/oracle/models/goalTypes"
 	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/templates"
 	"github.com/Zetkolink/oracle/models/userGoals"
 	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
 )
 
+// templateHorizon number of days ahead templates are materialised for.
+const templateHorizon = 7
+
 var (
 	ErrConflict = errors.New("goal conflict")
 )
 
 type Manager struct {
-	models ModelsSet
+	notificator *notificator.Notificator
+	models      ModelsSet
 }
 
 type Config struct {
-	Models ModelsSet
+	// Notificator plans user reminders, which follow awaking goals.
+	Notificator *notificator.Notificator
+	Models      ModelsSet
 }
 
 type ModelsSet struct {
-	Users     *users.Model
-	Goals     *goals.Model
-	GoalTypes *goalTypes.Model
-	UserGoals *userGoals.Model
+	Users     users.Repository
+	Goals     goals.Repository
+	GoalTypes goalTypes.Repository
+	UserGoals userGoals.Repository
+	Templates templates.Repository
 }
 
 func NewManager(config Config) *Manager {
-	return &Manager{models: config.Models}
+	return &Manager{
+		notificator: config.Notificator,
+		models:      config.Models,
+	}
 }
 
 func (m *Manager) AssignGoal(ctx context.Context, user *users.User,
@@ -70,6 +83,9 @@ func (m *Manager) AssignGoal(ctx context.Context, user *users.User,
 			return nil, err
 		}
 
+		uGoal.GoalID = goal.ID
+		m.planReminders(ctx, user, goal.Type)
+
 		return uGoal, nil
 	}
 
@@ -101,6 +117,8 @@ func (m *Manager) AssignGoal(ctx context.Context, user *users.User,
 		return nil, err
 	}
 
+	m.planReminders(ctx, user, goal.Type)
+
 	return uGoal, nil
 }
 
@@ -231,3 +249,148 @@ func (m *Manager) UserGoals(ctx context.Context, user *users.User,
 
 	return gls, nil
 }
+
+// Materialize assign template goal to matching dates from today
+// until horizon. Dates which already have goal of template type are
+// skipped, so user choices are kept.
+func (m *Manager) Materialize(ctx context.Context, user *users.User,
+	tpl *templates.Template) error {
+
+	uDay, err := user.Day(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	today := templates.Day(*uDay)
+	from := today
+
+	if tpl.LastDate != nil && !templates.Day(*tpl.LastDate).Before(today) {
+		from = templates.Day(*tpl.LastDate).AddDate(0, 0, 1)
+	}
+
+	if from.Before(templates.Day(tpl.StartDate)) {
+		from = templates.Day(tpl.StartDate)
+	}
+
+	to := today.AddDate(0, 0, templateHorizon)
+
+	if from.After(to) {
+		return nil
+	}
+
+	goal, err := m.models.Goals.Get(ctx, tpl.GoalID)
+
+	if err != nil {
+		return err
+	}
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if !tpl.Match(date) {
+			continue
+		}
+
+		midday, err := user.Midday(date)
+
+		if err != nil {
+			return err
+		}
+
+		uGoal, err := m.GetByType(ctx, user, *midday, tpl.Type)
+
+		if err != nil {
+			return err
+		}
+
+		if uGoal != nil {
+			continue
+		}
+
+		_, err = m.AssignGoal(ctx, user, goal, *midday)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	tpl.LastDate = &to
+
+	err = m.models.Templates.Update(ctx, tpl)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unschedule remove not started goals materialised by template
+// after today, so template can be paused, edited or cancelled.
+func (m *Manager) Unschedule(ctx context.Context, user *users.User,
+	tpl *templates.Template) error {
+
+	if tpl.LastDate == nil {
+		return nil
+	}
+
+	uDay, err := user.Day(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	from := templates.Day(*uDay).AddDate(0, 0, 1)
+	to := templates.Day(*tpl.LastDate)
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		midday, err := user.Midday(date)
+
+		if err != nil {
+			return err
+		}
+
+		uGoal, err := m.GetByType(ctx, user, *midday, tpl.Type)
+
+		if err != nil {
+			return err
+		}
+
+		if uGoal == nil || uGoal.GoalID != tpl.GoalID ||
+			uGoal.Phase != userGoals.PhasePlanning {
+
+			continue
+		}
+
+		err = m.RejectGoal(ctx, uGoal.ID)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	m.planReminders(ctx, user, tpl.Type)
+
+	tpl.LastDate = nil
+
+	err = m.models.Templates.Update(ctx, tpl)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// planReminders plan user reminders again when awaking goal is
+// changed, so they follow new wake up time.
+func (m *Manager) planReminders(ctx context.Context, user *users.User, gType int64) {
+	if gType != goalTypes.Awaking || m.notificator == nil {
+		return
+	}
+
+	err := m.notificator.PlanUser(ctx, user)
+
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/outbox"
+	"github.com/Zetkolink/oracle/models/reminders"
+	"github.com/Zetkolink/oracle/models/templates"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+)
+
+const codeTaskList = "task_list"
+
+// Reminders planned before awaking goal change must follow it.
+func TestAssignGoalPlansReminders(t *testing.T) {
+	stale := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		goals   []*goals.Goal
+		replans bool
+	}{
+		{name: "awaking goal",
+			goals:   []*goals.Goal{{ID: int64(goals.Awaking8), Type: goalTypes.Awaking}},
+			replans: true},
+		{name: "changed awaking goal",
+			goals: []*goals.Goal{
+				{ID: int64(goals.Awaking8), Type: goalTypes.Awaking},
+				{ID: int64(goals.Awaking10), Type: goalTypes.Awaking},
+			},
+			replans: true},
+		{name: "other goal",
+			goals:   []*goals.Goal{{ID: 100, Type: goalTypes.Awaking + 1}},
+			replans: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &users.User{
+				ID:       1,
+				Timezone: "UTC",
+				DayStart: users.DefaultDayStart,
+				Active:   true,
+			}
+
+			usersModel := users.NewMemory()
+			userGoalsModel := userGoals.NewMemory()
+			remindersModel := reminders.NewMemory()
+			goalTypesModel := goalTypes.NewMemory(
+				&goalTypes.GoalType{ID: goalTypes.Awaking},
+				&goalTypes.GoalType{ID: goalTypes.Awaking + 1},
+			)
+
+			err := usersModel.Create(ctx, user)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = remindersModel.Set(ctx, &reminders.Reminder{
+				UserID: user.ID,
+				Code:   codeTaskList,
+				Due:    stale,
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := NewManager(Config{
+				Notificator: notificator.NewNotificator(notificator.Config{
+					Models: notificator.ModelsSet{
+						Users:     usersModel,
+						UserGoals: userGoalsModel,
+						GoalTypes: goalTypesModel,
+						Outbox:    outbox.NewMemory(),
+						Reminders: remindersModel,
+					},
+				}),
+				Models: ModelsSet{
+					Users:     usersModel,
+					Goals:     goals.NewMemory(tt.goals...),
+					GoalTypes: goalTypesModel,
+					UserGoals: userGoalsModel,
+					Templates: templates.NewMemory(),
+				},
+			})
+
+			for i, goal := range tt.goals {
+				if i > 0 {
+					// Reset plan to see the change replans it again.
+					err = remindersModel.Set(ctx, &reminders.Reminder{
+						UserID: user.ID,
+						Code:   codeTaskList,
+						Due:    stale,
+					})
+
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				_, err = m.AssignGoal(ctx, user, goal, time.Now().AddDate(0, 0, 1))
+
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reminder, err := remindersModel.Get(ctx, user.ID, codeTaskList)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if reminder == nil {
+				t.Fatal("reminder is removed")
+			}
+
+			if replanned := !reminder.Due.Equal(stale); replanned != tt.replans {
+				t.Errorf("due = %s, replanned %t, want %t", reminder.Due, replanned, tt.replans)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Zetkolink/oracle/migrations"
+)
+
+var (
+	errMigrateUsage = errors.New("usage: oracle migrate up|down|status")
+)
+
+// runMigrate run migrate subcommand.
+func runMigrate(args []string) error {
+	if len(args) != 1 {
+		return errMigrateUsage
+	}
+
+	db, err := sql.Open("postgres", cfg.Db.GetConn())
+
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return migrateUp(ctx, db)
+	case "down":
+		migration, err := migrations.NewMigrator(migrations.Config{Db: db}).Down(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Reverted migration %d %s", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrations.NewMigrator(migrations.Config{Db: db}).Status(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+		return w.Flush()
+	default:
+		return errMigrateUsage
+	}
+
+	return nil
+}
+
+// migrateUp apply pending migrations.
+func migrateUp(ctx context.Context, db *sql.DB) error {
+	applied, err := migrations.NewMigrator(migrations.Config{Db: db}).Up(ctx)
+
+	for _, migration := range applied {
+		log.Printf("Applied migration %d %s", migration.Version, migration.Name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
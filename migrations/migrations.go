@@ -0,0 +1,249 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// lockID advisory lock key which serializes migrators of several instances.
+const lockID = 7343125
+
+var (
+	// ErrNoApplied no applied migrations to revert.
+	ErrNoApplied = errors.New("no applied migrations")
+)
+
+// Migration schema migration.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status migration status.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// Migrator applies embedded migrations.
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// Config configuration for Migrator.
+type Config struct {
+	Db *sql.DB
+}
+
+// NewMigrator create new instance of Migrator.
+func NewMigrator(config Config) *Migrator {
+	return &Migrator{
+		db:         config.Db,
+		migrations: migrations,
+	}
+}
+
+// Up apply all pending migrations and return applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	conn, err := m.lock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.unlock(conn)
+
+	applied, err := m.applied(ctx, conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err = m.exec(ctx, conn, migration.Up,
+			`INSERT INTO schema_migrations ("version", "name") VALUES ($1, $2)`,
+			migration.Version, migration.Name)
+
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down revert last applied migration and return it.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	conn, err := m.lock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.unlock(conn)
+
+	applied, err := m.applied(ctx, conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err = m.exec(ctx, conn, migration.Down,
+			`DELETE FROM schema_migrations WHERE "version" = $1`,
+			migration.Version)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return migration, nil
+	}
+
+	return nil, ErrNoApplied
+}
+
+// Status get status of all known migrations.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	conn, err := m.db.Conn(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*Status, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		status := &Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, error) {
+	conn, err := m.db.Conn(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID)
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (m *Migrator) unlock(conn *sql.Conn) {
+	_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+	conn.Close()
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+									"version"    bigint PRIMARY KEY,
+									"name"       text NOT NULL,
+									"applied_at" timestamptz NOT NULL DEFAULT now()
+								)`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT "version", "applied_at"
+									FROM schema_migrations`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+
+		err = rows.Scan(&version, &appliedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return applied, nil
+}
+
+// exec run migration query and bookkeeping query in one transaction.
+func (m *Migrator) exec(ctx context.Context, conn *sql.Conn, query string,
+	mark string, args ...interface{}) error {
+
+	tx, err := conn.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, mark, args...)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,372 @@
+package migrations
+
+// migrations embedded schema migrations ordered by version.
+// Init and seed match schema of deployments created before
+// migrations, so they are applied over existing tables and rows.
+var migrations = []*Migration{
+	{
+		Version: 1,
+		Name:    "init",
+		Up: `
+			CREATE TABLE IF NOT EXISTS users (
+				"id"         bigint PRIMARY KEY,
+				"first_name" text NOT NULL DEFAULT '',
+				"last_name"  text NOT NULL DEFAULT '',
+				"active"     boolean NOT NULL DEFAULT true,
+				"timezone"   text NOT NULL,
+				"city"       text NOT NULL DEFAULT '',
+				"state"      text NOT NULL DEFAULT '',
+				"created_at" timestamptz NOT NULL DEFAULT now()
+			);
+
+			CREATE TABLE IF NOT EXISTS white_list (
+				"user_id" bigint PRIMARY KEY
+			);
+
+			CREATE TABLE IF NOT EXISTS goal_types (
+				"id"        bigserial PRIMARY KEY,
+				"name"      text NOT NULL,
+				"points"    bigint NOT NULL DEFAULT 0,
+				"evaluated" boolean NOT NULL DEFAULT false,
+				"from_list" boolean NOT NULL DEFAULT false
+			);
+
+			CREATE TABLE IF NOT EXISTS goals (
+				"id"          bigserial PRIMARY KEY,
+				"type"        bigint NOT NULL REFERENCES goal_types ("id"),
+				"description" text NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS goals_type_idx ON goals ("type");
+
+			CREATE TABLE IF NOT EXISTS user_goals (
+				"id"      bigserial PRIMARY KEY,
+				"user_id" bigint NOT NULL REFERENCES users ("id"),
+				"goal_id" bigint NOT NULL REFERENCES goals ("id"),
+				"type"    bigint NOT NULL REFERENCES goal_types ("id"),
+				"phase"   text NOT NULL,
+				"status"  text NOT NULL,
+				"from"    timestamptz NOT NULL,
+				"to"      timestamptz NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS user_goals_user_idx ON user_goals ("user_id", "from", "to");
+			CREATE INDEX IF NOT EXISTS user_goals_phase_idx ON user_goals ("phase");
+
+			CREATE TABLE IF NOT EXISTS evaluations (
+				"id"           bigserial PRIMARY KEY,
+				"user_goal_id" bigint NOT NULL REFERENCES user_goals ("id") ON DELETE CASCADE,
+				"user_id"      bigint NOT NULL REFERENCES users ("id"),
+				"evaluation"   boolean NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS evaluations_user_goal_idx ON evaluations ("user_goal_id");
+
+			CREATE TABLE IF NOT EXISTS for_rate (
+				"user_id"      bigint NOT NULL REFERENCES users ("id"),
+				"user_goal_id" bigint NOT NULL REFERENCES user_goals ("id") ON DELETE CASCADE,
+				PRIMARY KEY ("user_id", "user_goal_id")
+			);
+
+			CREATE TABLE IF NOT EXISTS keyboards (
+				"id"       bigserial PRIMARY KEY,
+				"service"  text NOT NULL,
+				"name"     text NOT NULL,
+				"keyboard" text NOT NULL,
+				UNIQUE ("service", "name")
+			);`,
+		Down: `
+			DROP TABLE keyboards;
+			DROP TABLE for_rate;
+			DROP TABLE evaluations;
+			DROP TABLE user_goals;
+			DROP TABLE goals;
+			DROP TABLE goal_types;
+			DROP TABLE white_list;
+			DROP TABLE users;`,
+	},
+	{
+		Version: 2,
+		Name:    "seed",
+		Up: `
+			INSERT INTO goal_types ("id", "name", "points", "evaluated", "from_list")
+			VALUES (1, 'Пробуждение', 10, false, true)
+			ON CONFLICT ("id") DO NOTHING;
+
+			SELECT setval('goal_types_id_seq', (SELECT max("id") FROM goal_types));
+
+			INSERT INTO goals ("id", "type", "description")
+			VALUES (1, 1, 'Подъём в 6:00'),
+				(2, 1, 'Подъём в 8:00'),
+				(3, 1, 'Подъём в 10:00')
+			ON CONFLICT ("id") DO NOTHING;
+
+			SELECT setval('goals_id_seq', (SELECT max("id") FROM goals));
+
+			INSERT INTO keyboards ("service", "name", "keyboard")
+			SELECT * FROM (VALUES ('vk', 'register', '{"one_time":true,"inline":false,"buttons":[[{"color":"positive","action":{"label":"Начать","type":"text","payload":"{\"command\":\"register\",\"params\":null}"}}]]}'),
+				('vk', 'menu', '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}]]}'),
+				('vk', 'tasks', '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Текущие","type":"text","payload":"{\"command\":\"current_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Отметить","type":"text","payload":"{\"command\":\"update_task\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Просмотр","type":"text","payload":"{\"command\":\"observe_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Планирование","type":"text","payload":"{\"command\":\"change_task\",\"params\":null}"}}],[{"color":"secondary","action":{"label":"Меню","type":"text","payload":"{\"command\":\"menu\",\"params\":null}"}}]]}') AS seed ("service", "name", "keyboard")
+			WHERE NOT EXISTS (
+				SELECT 1 FROM keyboards k
+				WHERE k."service" = seed."service" AND k."name" = seed."name"
+			);`,
+		Down: `
+			DELETE FROM keyboards WHERE "service" = 'vk'
+				AND "name" IN ('register', 'menu', 'tasks');
+			DELETE FROM goals WHERE "id" IN (1, 2, 3);
+			DELETE FROM goal_types WHERE "id" = 1;`,
+	},
+	{
+		Version: 3,
+		Name:    "scores",
+		Up: `
+			CREATE TABLE goal_scores (
+				"user_goal_id" bigint PRIMARY KEY REFERENCES user_goals ("id") ON DELETE CASCADE,
+				"user_id"      bigint NOT NULL REFERENCES users ("id"),
+				"date"         date NOT NULL,
+				"points"       bigint NOT NULL
+			);
+
+			CREATE TABLE scores (
+				"user_id" bigint NOT NULL REFERENCES users ("id"),
+				"period"  text NOT NULL,
+				"start"   date NOT NULL,
+				"points"  bigint NOT NULL DEFAULT 0,
+				PRIMARY KEY ("user_id", "period", "start")
+			);
+
+			CREATE INDEX scores_leaderboard_idx ON scores ("period", "start", "points" DESC);
+
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';`,
+		Down: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';
+
+			DROP TABLE scores;
+			DROP TABLE goal_scores;`,
+	},
+	{
+		Version: 4,
+		Name:    "reviews",
+		Up: `
+			CREATE TABLE reviews (
+				"user_goal_id" bigint PRIMARY KEY REFERENCES user_goals ("id") ON DELETE CASCADE,
+				"status"       text NOT NULL,
+				"round"        bigint NOT NULL DEFAULT 1,
+				"deadline"     timestamptz NOT NULL,
+				"decided_at"   timestamptz
+			);
+
+			CREATE INDEX reviews_status_idx ON reviews ("status", "deadline");
+
+			CREATE TABLE proofs (
+				"id"           bigserial PRIMARY KEY,
+				"user_goal_id" bigint NOT NULL REFERENCES user_goals ("id") ON DELETE CASCADE,
+				"kind"         text NOT NULL,
+				"text"         text NOT NULL DEFAULT '',
+				"attachments"  jsonb NOT NULL DEFAULT '[]',
+				"created_at"   timestamptz NOT NULL DEFAULT now()
+			);
+
+			CREATE INDEX proofs_user_goal_idx ON proofs ("user_goal_id");`,
+		Down: `
+			DROP TABLE proofs;
+			DROP TABLE reviews;`,
+	},
+	{
+		Version: 5,
+		Name:    "aliases",
+		Up: `
+			CREATE TABLE aliases (
+				"viewer_id"  bigint NOT NULL REFERENCES users ("id"),
+				"subject_id" bigint NOT NULL REFERENCES users ("id"),
+				"generation" bigint NOT NULL DEFAULT 1,
+				"alias"      text NOT NULL,
+				PRIMARY KEY ("viewer_id", "subject_id")
+			);
+
+			CREATE INDEX aliases_subject_idx ON aliases ("subject_id");`,
+		Down: `
+			DROP TABLE aliases;`,
+	},
+	{
+		Version: 6,
+		Name:    "requires_proof",
+		Up: `
+			ALTER TABLE goal_types ADD COLUMN "requires_proof" boolean NOT NULL DEFAULT false;`,
+		Down: `
+			ALTER TABLE goal_types DROP COLUMN "requires_proof";`,
+	},
+	{
+		Version: 7,
+		Name:    "goal_templates",
+		Up: `
+			CREATE TABLE goal_templates (
+				"id"         bigserial PRIMARY KEY,
+				"user_id"    bigint NOT NULL REFERENCES users ("id"),
+				"goal_id"    bigint NOT NULL REFERENCES goals ("id"),
+				"type"       bigint NOT NULL REFERENCES goal_types ("id"),
+				"rule"       text NOT NULL,
+				"weekdays"   bigint NOT NULL DEFAULT 0,
+				"interval"   bigint NOT NULL DEFAULT 0,
+				"start_date" date NOT NULL,
+				"last_date"  date,
+				"paused"     boolean NOT NULL DEFAULT false,
+				"created_at" timestamptz NOT NULL DEFAULT now()
+			);
+
+			CREATE INDEX goal_templates_user_idx ON goal_templates ("user_id");
+
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Текущие","type":"text","payload":"{\"command\":\"current_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Отметить","type":"text","payload":"{\"command\":\"update_task\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Просмотр","type":"text","payload":"{\"command\":\"observe_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Планирование","type":"text","payload":"{\"command\":\"change_task\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Повторы","type":"text","payload":"{\"command\":\"recurring\",\"params\":null}"}}],[{"color":"secondary","action":{"label":"Меню","type":"text","payload":"{\"command\":\"menu\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'tasks';`,
+		Down: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Текущие","type":"text","payload":"{\"command\":\"current_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Отметить","type":"text","payload":"{\"command\":\"update_task\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Просмотр","type":"text","payload":"{\"command\":\"observe_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Планирование","type":"text","payload":"{\"command\":\"change_task\",\"params\":null}"}}],[{"color":"secondary","action":{"label":"Меню","type":"text","payload":"{\"command\":\"menu\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'tasks';
+
+			DROP TABLE goal_templates;`,
+	},
+	{
+		Version: 8,
+		Name:    "streaks",
+		Up: `
+			CREATE TABLE streaks (
+				"user_id"   bigint NOT NULL REFERENCES users ("id"),
+				"type"      bigint NOT NULL REFERENCES goal_types ("id"),
+				"current"   bigint NOT NULL DEFAULT 0,
+				"best"      bigint NOT NULL DEFAULT 0,
+				"last_date" date,
+				"broken_at" date,
+				PRIMARY KEY ("user_id", "type")
+			);`,
+		Down: `
+			DROP TABLE streaks;`,
+	},
+	{
+		Version: 9,
+		Name:    "stats_menu",
+		Up: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}},{"color":"primary","action":{"label":"Статистика","type":"text","payload":"{\"command\":\"to_stats\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';`,
+		Down: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';`,
+	},
+	{
+		Version: 10,
+		Name:    "erased_scores",
+		Up: `
+			CREATE TABLE erased_scores (
+				"period" text NOT NULL,
+				"start"  date NOT NULL,
+				"users"  bigint NOT NULL DEFAULT 0,
+				"points" bigint NOT NULL DEFAULT 0,
+				PRIMARY KEY ("period", "start")
+			);`,
+		Down: `
+			DROP TABLE erased_scores;`,
+	},
+	{
+		Version: 11,
+		Name:    "account_menu",
+		Up: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}},{"color":"primary","action":{"label":"Статистика","type":"text","payload":"{\"command\":\"to_stats\",\"params\":null}"}}],[{"color":"negative","action":{"label":"Удалить аккаунт","type":"text","payload":"{\"command\":\"to_account\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';`,
+		Down: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}},{"color":"primary","action":{"label":"Статистика","type":"text","payload":"{\"command\":\"to_stats\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';`,
+	},
+	{
+		Version: 12,
+		Name:    "user_settings",
+		Up: `
+			ALTER TABLE users
+				ADD COLUMN "morning_hour" smallint,
+				ADD COLUMN "evening_hour" smallint,
+				ADD COLUMN "planning_hour" smallint;
+
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}},{"color":"primary","action":{"label":"Статистика","type":"text","payload":"{\"command\":\"to_stats\",\"params\":null}"}}],[{"color":"secondary","action":{"label":"Настройки","type":"text","payload":"{\"command\":\"to_settings\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';`,
+		Down: `
+			UPDATE keyboards SET "keyboard" = '{"one_time":false,"inline":false,"buttons":[[{"color":"primary","action":{"label":"Задачи","type":"text","payload":"{\"command\":\"to_tasks\",\"params\":null}"}},{"color":"primary","action":{"label":"Оценка","type":"text","payload":"{\"command\":\"to_rate\",\"params\":null}"}}],[{"color":"primary","action":{"label":"Рейтинг","type":"text","payload":"{\"command\":\"to_rating\",\"params\":null}"}},{"color":"primary","action":{"label":"Статистика","type":"text","payload":"{\"command\":\"to_stats\",\"params\":null}"}}],[{"color":"negative","action":{"label":"Удалить аккаунт","type":"text","payload":"{\"command\":\"to_account\",\"params\":null}"}}]]}'
+			WHERE "service" = 'vk' AND "name" = 'menu';
+
+			ALTER TABLE users
+				DROP COLUMN "morning_hour",
+				DROP COLUMN "evening_hour",
+				DROP COLUMN "planning_hour";`,
+	},
+	{
+		Version: 13,
+		Name:    "day_start",
+		Up: `
+			ALTER TABLE users ADD COLUMN "day_start" smallint NOT NULL DEFAULT 6
+				CHECK ("day_start" BETWEEN 0 AND 23);`,
+		Down: `
+			ALTER TABLE users DROP COLUMN "day_start";`,
+	},
+	{
+		Version: 14,
+		Name:    "outbox",
+		Up: `
+			CREATE TABLE outbox (
+				"id"           bigserial PRIMARY KEY,
+				"key"          text NOT NULL UNIQUE,
+				"user_id"      bigint NOT NULL REFERENCES users ("id") ON DELETE CASCADE,
+				"code"         text NOT NULL,
+				"text"         text NOT NULL DEFAULT '',
+				"params"       jsonb NOT NULL DEFAULT 'null',
+				"status"       text NOT NULL DEFAULT 'pending',
+				"attempts"     integer NOT NULL DEFAULT 0,
+				"next_attempt" timestamptz NOT NULL DEFAULT now(),
+				"last_error"   text NOT NULL DEFAULT '',
+				"created_at"   timestamptz NOT NULL DEFAULT now(),
+				"sent_at"      timestamptz
+			);
+
+			CREATE INDEX outbox_due_idx ON outbox ("next_attempt") WHERE "status" = 'pending';
+			CREATE INDEX outbox_user_idx ON outbox ("user_id");
+
+			CREATE TABLE outbox_attempts (
+				"message_id" bigint NOT NULL REFERENCES outbox ("id") ON DELETE CASCADE,
+				"number"     integer NOT NULL,
+				"error"      text NOT NULL DEFAULT '',
+				"created_at" timestamptz NOT NULL DEFAULT now(),
+				PRIMARY KEY ("message_id", "number")
+			);`,
+		Down: `
+			DROP TABLE outbox_attempts;
+			DROP TABLE outbox;`,
+	},
+	{
+		Version: 15,
+		Name:    "evaluations_unique",
+		Up: `
+			DELETE FROM evaluations e
+				USING evaluations d
+				WHERE e."user_id" = d."user_id"
+				AND e."user_goal_id" = d."user_goal_id"
+				AND e."id" > d."id";
+
+			CREATE UNIQUE INDEX evaluations_user_user_goal_idx
+				ON evaluations ("user_id", "user_goal_id");`,
+		Down: `
+			DROP INDEX evaluations_user_user_goal_idx;`,
+	},
+	{
+		Version: 16,
+		Name:    "telegram_keyboards",
+		Up: `
+			INSERT INTO keyboards ("service", "name", "keyboard")
+			SELECT 'telegram', vk."name", vk."keyboard"
+				FROM keyboards vk
+				WHERE vk."service" = 'vk'
+				AND NOT EXISTS (
+					SELECT 1 FROM keyboards k
+					WHERE k."service" = 'telegram' AND k."name" = vk."name"
+				);`,
+		Down: `
+			DELETE FROM keyboards WHERE "service" = 'telegram';`,
+	},
+}
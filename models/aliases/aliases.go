@@ -0,0 +1,145 @@
+package aliases
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Repository type represent aliases storage.
+type Repository interface {
+	// Create create new alias, does nothing if already exists.
+	Create(ctx context.Context, alias *Alias) error
+
+	// Get get alias of subject shown to viewer.
+	Get(ctx context.Context, viewerID int64, subjectID int64) (*Alias, error)
+
+	// Update update alias.
+	Update(ctx context.Context, alias *Alias) error
+
+	// ListBySubject get aliases of subject, all if subjectID is 0.
+	ListBySubject(ctx context.Context, subjectID int64) ([]*Alias, error)
+
+	// DeleteByUser delete aliases shown to user and aliases of user.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Alias type represent pseudonym of subject user shown to viewer user.
+type Alias struct {
+	ViewerID   int64  `json:"viewer_id"`
+	SubjectID  int64  `json:"subject_id"`
+	Generation int64  `json:"generation"`
+	Alias      string `json:"alias"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Create create new alias, does nothing if already exists.
+func (m *Model) Create(ctx context.Context, alias *Alias) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO aliases
+									("viewer_id", "subject_id", "generation", "alias")
+								VALUES ($1, $2, $3, $4)
+								ON CONFLICT DO NOTHING`,
+		alias.ViewerID, alias.SubjectID, alias.Generation, alias.Alias)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get get alias of subject shown to viewer.
+func (m *Model) Get(ctx context.Context, viewerID int64, subjectID int64) (*Alias, error) {
+	var alias Alias
+
+	err := m.db.QueryRowContext(ctx, `SELECT
+									"viewer_id", "subject_id", "generation", "alias"
+									FROM aliases
+								WHERE "viewer_id" = $1 AND "subject_id" = $2`,
+		viewerID, subjectID).
+		Scan(&alias.ViewerID, &alias.SubjectID, &alias.Generation, &alias.Alias)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &alias, nil
+}
+
+// Update update alias.
+func (m *Model) Update(ctx context.Context, alias *Alias) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE aliases SET
+									"generation" = $3, "alias" = $4
+								WHERE "viewer_id" = $1 AND "subject_id" = $2`,
+		alias.ViewerID, alias.SubjectID, alias.Generation, alias.Alias)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListBySubject get aliases of subject, all if subjectID is 0.
+func (m *Model) ListBySubject(ctx context.Context, subjectID int64) ([]*Alias, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"viewer_id", "subject_id", "generation", "alias"
+									FROM aliases
+									WHERE $1 = 0 OR "subject_id" = $1`, subjectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list []*Alias
+
+	for rows.Next() {
+		var alias Alias
+
+		err = rows.Scan(&alias.ViewerID, &alias.SubjectID,
+			&alias.Generation, &alias.Alias)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, &alias)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return list, nil
+}
+
+// DeleteByUser delete aliases shown to user and aliases of user.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM aliases
+								WHERE "viewer_id" = $1 OR "subject_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package aliases
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory aliases storage.
+type Memory struct {
+	mu      sync.RWMutex
+	aliases map[[2]int64]Alias
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		aliases: make(map[[2]int64]Alias),
+	}
+}
+
+// Create create new alias, does nothing if already exists.
+func (m *Memory) Create(_ context.Context, alias *Alias) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]int64{alias.ViewerID, alias.SubjectID}
+
+	if _, ok := m.aliases[key]; !ok {
+		m.aliases[key] = *alias
+	}
+
+	return nil
+}
+
+// Get get alias of subject shown to viewer.
+func (m *Memory) Get(_ context.Context, viewerID int64, subjectID int64) (*Alias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alias, ok := m.aliases[[2]int64{viewerID, subjectID}]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &alias, nil
+}
+
+// Update update alias.
+func (m *Memory) Update(_ context.Context, alias *Alias) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]int64{alias.ViewerID, alias.SubjectID}
+
+	if _, ok := m.aliases[key]; ok {
+		m.aliases[key] = *alias
+	}
+
+	return nil
+}
+
+// ListBySubject get aliases of subject, all if subjectID is 0.
+func (m *Memory) ListBySubject(_ context.Context, subjectID int64) ([]*Alias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Alias
+
+	for _, alias := range m.aliases {
+		if subjectID != 0 && alias.SubjectID != subjectID {
+			continue
+		}
+
+		alias := alias
+		list = append(list, &alias)
+	}
+
+	return list, nil
+}
+
+// DeleteByUser delete aliases shown to user and aliases of user.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.aliases {
+		if key[0] == userID || key[1] == userID {
+			delete(m.aliases, key)
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,30 @@ import (
 	"database/sql"
 )
 
+// Repository type represent evaluations storage.
+type Repository interface {
+	// Create create new evaluation.
+	Create(ctx context.Context, eval *Evaluation) error
+
+	// GetResult get evaluation result by user goal.
+	GetResult(ctx context.Context, uGoalID int64) (bool, error)
+
+	// List get evaluations list.
+	List(ctx context.Context, user int64) ([]*Evaluation, error)
+
+	// ListByUserGoal get evaluations of user goal.
+	ListByUserGoal(ctx context.Context, uGoalID int64) ([]*Evaluation, error)
+
+	// Count count evaluations of user goal.
+	Count(ctx context.Context, uGoalID int64) (int64, error)
+
+	// DeleteByUserGoal delete evaluations of user goal.
+	DeleteByUserGoal(ctx context.Context, uGoalID int64) error
+
+	// DeleteByUser delete evaluations given by user.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db *sql.DB
@@ -51,7 +75,7 @@ func (m *Model) GetResult(ctx context.Context, uGoalID int64) (bool, error) {
 	var value int64
 
 	err := m.db.QueryRowContext(ctx, `SELECT 
-        sum(CASE WHEN evaluation THEN 1 ELSE -1 END)  
+        COALESCE(sum(CASE WHEN evaluation THEN 1 ELSE -1 END), 0)
 		from evaluations
 		WHERE user_goal_id = $1`, uGoalID).
 		Scan(&value)
@@ -99,3 +123,79 @@ func (m *Model) List(ctx context.Context, user int64) ([]*Evaluation, error) {
 
 	return evals, nil
 }
+
+// ListByUserGoal get evaluations of user goal.
+func (m *Model) ListByUserGoal(ctx context.Context, uGoalID int64) ([]*Evaluation, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"id", "user_goal_id", "user_id", "evaluation"
+									FROM evaluations
+									WHERE "user_goal_id" = $1
+									ORDER BY "id"`, uGoalID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var evals []*Evaluation
+
+	for rows.Next() {
+		var eval Evaluation
+
+		err = rows.Scan(&eval.ID, &eval.UserGoalID, &eval.UserID,
+			&eval.Evaluation)
+
+		if err != nil {
+			return nil, err
+		}
+
+		evals = append(evals, &eval)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return evals, nil
+}
+
+// Count count evaluations of user goal.
+func (m *Model) Count(ctx context.Context, uGoalID int64) (int64, error) {
+	var count int64
+
+	err := m.db.QueryRowContext(ctx, `SELECT count(*)
+		FROM evaluations
+		WHERE "user_goal_id" = $1`, uGoalID).
+		Scan(&count)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteByUserGoal delete evaluations of user goal.
+func (m *Model) DeleteByUserGoal(ctx context.Context, uGoalID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM evaluations
+		WHERE "user_goal_id" = $1`, uGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByUser delete evaluations given by user.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM evaluations
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
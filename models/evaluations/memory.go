@@ -0,0 +1,148 @@
+package evaluations
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory evaluations storage.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int64
+	evals  []Evaluation
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Create create new evaluation.
+func (m *Memory) Create(_ context.Context, eval *Evaluation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	stored := *eval
+	stored.ID = m.lastID
+	m.evals = append(m.evals, stored)
+
+	return nil
+}
+
+// GetResult get evaluation result by user goal.
+func (m *Memory) GetResult(_ context.Context, uGoalID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var value int64
+
+	for _, eval := range m.evals {
+		if eval.UserGoalID != uGoalID {
+			continue
+		}
+
+		if eval.Evaluation {
+			value++
+		} else {
+			value--
+		}
+	}
+
+	return value >= 0, nil
+}
+
+// List get evaluations list.
+func (m *Memory) List(_ context.Context, user int64) ([]*Evaluation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var evals []*Evaluation
+
+	for _, eval := range m.evals {
+		if eval.UserID != user {
+			continue
+		}
+
+		eval := eval
+		evals = append(evals, &eval)
+	}
+
+	return evals, nil
+}
+
+// ListByUserGoal get evaluations of user goal.
+func (m *Memory) ListByUserGoal(_ context.Context, uGoalID int64) ([]*Evaluation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var evals []*Evaluation
+
+	for _, eval := range m.evals {
+		if eval.UserGoalID != uGoalID {
+			continue
+		}
+
+		eval := eval
+		evals = append(evals, &eval)
+	}
+
+	return evals, nil
+}
+
+// Count count evaluations of user goal.
+func (m *Memory) Count(_ context.Context, uGoalID int64) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+
+	for _, eval := range m.evals {
+		if eval.UserGoalID == uGoalID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// DeleteByUserGoal delete evaluations of user goal.
+func (m *Memory) DeleteByUserGoal(_ context.Context, uGoalID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	evals := m.evals[:0]
+
+	for _, eval := range m.evals {
+		if eval.UserGoalID != uGoalID {
+			evals = append(evals, eval)
+		}
+	}
+
+	m.evals = evals
+
+	return nil
+}
+
+// DeleteByUser delete evaluations given by user.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	evals := m.evals[:0]
+
+	for _, eval := range m.evals {
+		if eval.UserID != userID {
+			evals = append(evals, eval)
+		}
+	}
+
+	m.evals = evals
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ttl time event ID is remembered, VK retries
+// undelivered event for several minutes.
+const ttl = time.Hour
+
+// Repository type represent handled inbound events storage.
+type Repository interface {
+	// Claim mark event as handled, false is returned when it
+	// was claimed before, e.g. retried event got by other instance.
+	Claim(ctx context.Context, source string, id string) (bool, error)
+
+	// Release forget claimed event, e.g. when it is not handled,
+	// so it is claimed again on retry.
+	Release(ctx context.Context, source string, id string) error
+}
+
+// Model type represent model.
+type Model struct {
+	cache *redis.Client
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Cache *redis.Client
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		cache: config.Cache,
+	}
+
+	return m, nil
+}
+
+// Claim mark event as handled, false is returned when it
+// was claimed before, e.g. retried event got by other instance.
+func (m *Model) Claim(ctx context.Context, source string, id string) (bool, error) {
+	return m.cache.SetNX(ctx, key(source, id), 1, ttl).Result()
+}
+
+// Release forget claimed event, e.g. when it is not handled,
+// so it is claimed again on retry.
+func (m *Model) Release(ctx context.Context, source string, id string) error {
+	return m.cache.Del(ctx, key(source, id)).Err()
+}
+
+func key(source string, id string) string {
+	return fmt.Sprintf("event_%s_%s", source, id)
+}
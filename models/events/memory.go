@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory events storage.
+type Memory struct {
+	mu     sync.Mutex
+	events map[string]time.Time
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		events: make(map[string]time.Time),
+	}
+}
+
+// Claim mark event as handled, false is returned when it
+// was claimed before.
+func (m *Memory) Claim(_ context.Context, source string, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	k := key(source, id)
+
+	if expires, ok := m.events[k]; ok && now.Before(expires) {
+		return false, nil
+	}
+
+	m.events[k] = now.Add(ttl)
+
+	return true, nil
+}
+
+// Release forget claimed event.
+func (m *Memory) Release(_ context.Context, source string, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.events, key(source, id))
+
+	return nil
+}
@@ -3,8 +3,32 @@ package forRate
 import (
 	"context"
 	"database/sql"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
 )
 
+// Repository type represent for rate queue storage.
+type Repository interface {
+	// Get get for rate by user ID.
+	Get(ctx context.Context, userID int64) (*ForRate, error)
+
+	// Create put user goal to user queue, does nothing if already queued.
+	Create(ctx context.Context, forRate *ForRate) error
+
+	// Delete delete user goal from user queue, returns false if not queued.
+	Delete(ctx context.Context, userID int64, uGoalID int64) (bool, error)
+
+	// Rate delete user goal from user queue and create its evaluation
+	// in one transaction, returns false if not queued.
+	Rate(ctx context.Context, eval *evaluations.Evaluation) (bool, error)
+
+	// DeleteByUserGoal delete user goal from all queues.
+	DeleteByUserGoal(ctx context.Context, uGoalID int64) error
+
+	// DeleteByUser delete user queue.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db *sql.DB
@@ -37,7 +61,9 @@ func (m *Model) Get(ctx context.Context, userID int64) (*ForRate, error) {
 	err := m.db.QueryRowContext(ctx, `SELECT  
 									"user_id", "user_goal_id"
 									 FROM for_rate
-								WHERE "user_id" = $1`, userID).
+								WHERE "user_id" = $1
+								ORDER BY "user_goal_id"
+								LIMIT 1`, userID).
 		Scan(&forRate.UserID, &forRate.UserGoalID)
 
 	if err != nil {
@@ -50,3 +76,108 @@ func (m *Model) Get(ctx context.Context, userID int64) (*ForRate, error) {
 
 	return &forRate, nil
 }
+
+// Create put user goal to user queue, does nothing if already queued.
+func (m *Model) Create(ctx context.Context, forRate *ForRate) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO for_rate
+									("user_id", "user_goal_id")
+								VALUES ($1, $2)
+								ON CONFLICT DO NOTHING`,
+		forRate.UserID, forRate.UserGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete delete user goal from user queue, returns false if not queued.
+func (m *Model) Delete(ctx context.Context, userID int64, uGoalID int64) (bool, error) {
+	res, err := m.db.ExecContext(ctx, `DELETE FROM for_rate
+								WHERE "user_id" = $1 AND "user_goal_id" = $2`,
+		userID, uGoalID)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Rate delete user goal from user queue and create its evaluation
+// in one transaction, returns false if not queued.
+func (m *Model) Rate(ctx context.Context, eval *evaluations.Evaluation) (bool, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM for_rate
+								WHERE "user_id" = $1 AND "user_goal_id" = $2`,
+		eval.UserID, eval.UserGoalID)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, tx.Rollback()
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO evaluations
+    			("user_goal_id", "user_id", "evaluation") VALUES ($1, $2, $3)`,
+		eval.UserGoalID, eval.UserID, eval.Evaluation)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteByUserGoal delete user goal from all queues.
+func (m *Model) DeleteByUserGoal(ctx context.Context, uGoalID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM for_rate
+								WHERE "user_goal_id" = $1`, uGoalID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByUser delete user queue.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM for_rate
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
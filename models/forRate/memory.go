@@ -0,0 +1,147 @@
+package forRate
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory for rate queue storage.
+type Memory struct {
+	mu          sync.RWMutex
+	queue       []ForRate
+	evaluations evaluations.Repository
+}
+
+// NewMemory create new Memory with queued items,
+// rated items are evaluated in evals.
+func NewMemory(evals evaluations.Repository, items ...*ForRate) *Memory {
+	m := &Memory{
+		evaluations: evals,
+	}
+
+	for _, item := range items {
+		m.queue = append(m.queue, *item)
+	}
+
+	return m
+}
+
+// Get get for rate by user ID.
+func (m *Memory) Get(_ context.Context, userID int64) (*ForRate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var found *ForRate
+
+	for _, item := range m.queue {
+		if item.UserID != userID {
+			continue
+		}
+
+		if found == nil || item.UserGoalID < found.UserGoalID {
+			item := item
+			found = &item
+		}
+	}
+
+	return found, nil
+}
+
+// Create put user goal to user queue, does nothing if already queued.
+func (m *Memory) Create(_ context.Context, forRate *ForRate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, item := range m.queue {
+		if item == *forRate {
+			return nil
+		}
+	}
+
+	m.queue = append(m.queue, *forRate)
+
+	return nil
+}
+
+// Delete delete user goal from user queue, returns false if not queued.
+func (m *Memory) Delete(_ context.Context, userID int64, uGoalID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, item := range m.queue {
+		if item.UserID == userID && item.UserGoalID == uGoalID {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Rate delete user goal from user queue and create its evaluation,
+// returns false if not queued.
+func (m *Memory) Rate(ctx context.Context, eval *evaluations.Evaluation) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, item := range m.queue {
+		if item.UserID != eval.UserID || item.UserGoalID != eval.UserGoalID {
+			continue
+		}
+
+		err := m.evaluations.Create(ctx, eval)
+
+		if err != nil {
+			return false, err
+		}
+
+		m.queue = append(m.queue[:i], m.queue[i+1:]...)
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// DeleteByUserGoal delete user goal from all queues.
+func (m *Memory) DeleteByUserGoal(_ context.Context, uGoalID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queue := m.queue[:0]
+
+	for _, item := range m.queue {
+		if item.UserGoalID != uGoalID {
+			queue = append(queue, item)
+		}
+	}
+
+	m.queue = queue
+
+	return nil
+}
+
+// DeleteByUser delete user queue.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queue := m.queue[:0]
+
+	for _, item := range m.queue {
+		if item.UserID != userID {
+			queue = append(queue, item)
+		}
+	}
+
+	m.queue = queue
+
+	return nil
+}
@@ -4,14 +4,56 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
 )
 
 const Awaking = 1
 
+// cacheVersion version of cached goal types, bumped when GoalType
+// fields are added, so entries cached without them are not read.
+const cacheVersion = 2
+
+// cacheTTL time goal types are cached, so entries left by
+// previous cache versions do not live forever.
+const cacheTTL = 24 * time.Hour
+
+var listKey = fmt.Sprintf("goal_type_list_v%d", cacheVersion)
+
+// legacyListKey list key used before cache was versioned.
+const legacyListKey = "goal_type_list"
+
+var (
+	// ErrNotFound goal type not found.
+	ErrNotFound = errors.New("goal type not found")
+
+	// ErrInUse goal type referenced by goals.
+	ErrInUse = errors.New("goal type in use")
+)
+
+// Repository type represent goal types storage.
+type Repository interface {
+	// Get get goal type by id.
+	Get(ctx context.Context, id int64) (*GoalType, error)
+
+	// List get goal types.
+	List(ctx context.Context) ([]*GoalType, error)
+
+	// Create create new goal type.
+	Create(ctx context.Context, gType *GoalType) (int64, error)
+
+	// Update update goal type.
+	Update(ctx context.Context, gType *GoalType) error
+
+	// Delete delete goal type by id.
+	Delete(ctx context.Context, id int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db    *sql.DB
@@ -26,11 +68,12 @@ type ModelConfig struct {
 
 // GoalType type represent goal type.
 type GoalType struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	Points    int64  `json:"points"`
-	Evaluated bool   `json:"evaluated"`
-	FromList  bool   `json:"from_list"`
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Points        int64  `json:"points"`
+	Evaluated     bool   `json:"evaluated"`
+	FromList      bool   `json:"from_list"`
+	RequiresProof bool   `json:"requires_proof"`
 }
 
 // NewModel create new Model.
@@ -57,16 +100,23 @@ func (m *Model) Get(ctx context.Context, id int64) (*GoalType, error) {
 
 	err = m.db.QueryRowContext(ctx, `SELECT  
 									"id", "name","points", 
-       								"evaluated", "from_list"
+       								"evaluated", "from_list", "requires_proof"
 									     FROM goal_types
 								WHERE "id" = $1`,
 		id,
-	).Scan(&gt.ID, &gt.Name, &gt.Points, &gt.Evaluated, &gt.FromList)
+	).Scan(&gt.ID, &gt.Name, &gt.Points, &gt.Evaluated, &gt.FromList,
+		&gt.RequiresProof)
 
 	if err != nil {
 		return nil, err
 	}
 
+	err = m.setCache(ctx, &gt)
+
+	if err != nil {
+		log.Println(err)
+	}
+
 	return &gt, nil
 }
 
@@ -84,7 +134,7 @@ func (m *Model) List(ctx context.Context) ([]*GoalType, error) {
 
 	rows, err := m.db.QueryContext(ctx, `SELECT  
 									"id", "name","points", 
-       								"evaluated", "from_list"
+       								"evaluated", "from_list", "requires_proof"
 									FROM goal_types
 									ORDER BY "id"`)
 
@@ -98,7 +148,7 @@ func (m *Model) List(ctx context.Context) ([]*GoalType, error) {
 		var gt GoalType
 
 		err = rows.Scan(&gt.ID, &gt.Name, &gt.Points, &gt.Evaluated,
-			&gt.FromList)
+			&gt.FromList, &gt.RequiresProof)
 
 		if err != nil {
 			return nil, err
@@ -120,10 +170,93 @@ func (m *Model) List(ctx context.Context) ([]*GoalType, error) {
 	return gts, nil
 }
 
+// Create create new goal type.
+func (m *Model) Create(ctx context.Context, gType *GoalType) (int64, error) {
+	var id int64
+
+	err := m.db.QueryRowContext(ctx,
+		`INSERT INTO goal_types ("name", "points", "evaluated",
+					"from_list", "requires_proof")
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING "id"`,
+		gType.Name, gType.Points, gType.Evaluated, gType.FromList,
+		gType.RequiresProof).Scan(&id)
+
+	if err != nil {
+		return id, err
+	}
+
+	m.clearCache(ctx, id)
+
+	return id, nil
+}
+
+// Update update goal type.
+func (m *Model) Update(ctx context.Context, gType *GoalType) error {
+	res, err := m.db.ExecContext(ctx, `UPDATE goal_types SET
+									"name" = $2, "points" = $3,
+									"evaluated" = $4, "from_list" = $5,
+									"requires_proof" = $6
+								WHERE "id" = $1`,
+		gType.ID, gType.Name, gType.Points, gType.Evaluated, gType.FromList,
+		gType.RequiresProof)
+
+	if err != nil {
+		return err
+	}
+
+	m.clearCache(ctx, gType.ID)
+
+	return m.checkAffected(res)
+}
+
+// Delete delete goal type by id.
+func (m *Model) Delete(ctx context.Context, id int64) error {
+	res, err := m.db.ExecContext(ctx, `DELETE FROM goal_types
+								WHERE "id" = $1`, id)
+
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23503" {
+				return ErrInUse
+			}
+		}
+
+		return err
+	}
+
+	m.clearCache(ctx, id)
+
+	return m.checkAffected(res)
+}
+
+func (m *Model) checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// clearCache drop cached list and goal type.
+func (m *Model) clearCache(ctx context.Context, id int64) {
+	err := m.cache.Del(ctx, listKey, m.key(id)).Err()
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
 func (m *Model) listCache(ctx context.Context) ([]*GoalType, error) {
 	var gTypes []*GoalType
 
-	raw, err := m.cache.Get(ctx, "goal_type_list").Result()
+	raw, err := m.cache.Get(ctx, listKey).Result()
 
 	if err != nil {
 		return nil, err
@@ -145,13 +278,13 @@ func (m *Model) setListCache(ctx context.Context, gTypes []*GoalType) error {
 		return err
 	}
 
-	err = m.cache.Set(ctx, "goal_type_list", rawBytes, 0).Err()
+	err = m.cache.Set(ctx, listKey, rawBytes, cacheTTL).Err()
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return m.cache.Del(ctx, legacyListKey).Err()
 }
 
 func (m *Model) getCache(ctx context.Context, id int64) (*GoalType, error) {
@@ -179,15 +312,21 @@ func (m *Model) setCache(ctx context.Context, gType *GoalType) error {
 		return err
 	}
 
-	err = m.cache.Set(ctx, m.key(gType.ID), rawBytes, 0).Err()
+	err = m.cache.Set(ctx, m.key(gType.ID), rawBytes, cacheTTL).Err()
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return m.cache.Del(ctx, legacyKey(gType.ID)).Err()
 }
 
 func (m *Model) key(id int64) string {
+	return fmt.Sprintf("goal_type_v%d_%d", cacheVersion, id)
+}
+
+// legacyKey goal type key used before cache was versioned,
+// it had no TTL, so it is deleted when goal type is cached again.
+func legacyKey(id int64) string {
 	return fmt.Sprintf("goal_type_%d", id)
 }
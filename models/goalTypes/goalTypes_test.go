@@ -0,0 +1,300 @@
+package goalTypes
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// stored goal type returned by fake database.
+var stored = &GoalType{ID: 1, Name: "Подъём", Points: 10, RequiresProof: true}
+
+func init() {
+	sql.Register("goalTypesStub", dbStub{})
+}
+
+// dbStub minimal database driver which answers goal types queries
+// with stored goal type.
+type dbStub struct{}
+
+func (dbStub) Open(string) (driver.Conn, error) {
+	return dbStub{}, nil
+}
+
+func (dbStub) Prepare(string) (driver.Stmt, error) {
+	return dbStub{}, nil
+}
+
+func (dbStub) Close() error {
+	return nil
+}
+
+func (dbStub) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (dbStub) NumInput() int {
+	return -1
+}
+
+func (dbStub) Exec([]driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (dbStub) Query([]driver.Value) (driver.Rows, error) {
+	return &rowsStub{}, nil
+}
+
+type rowsStub struct {
+	done bool
+}
+
+func (r *rowsStub) Columns() []string {
+	return []string{"id", "name", "points", "evaluated", "from_list",
+		"requires_proof"}
+}
+
+func (r *rowsStub) Close() error {
+	return nil
+}
+
+func (r *rowsStub) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	dest[0] = stored.ID
+	dest[1] = stored.Name
+	dest[2] = stored.Points
+	dest[3] = stored.Evaluated
+	dest[4] = stored.FromList
+	dest[5] = stored.RequiresProof
+
+	return nil
+}
+
+// redisStub minimal Redis server which keeps strings.
+type redisStub struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+func newRedisStub(t *testing.T) (*redisStub, *redis.Client) {
+	stub := &redisStub{keys: make(map[string]string)}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go stub.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:       listener.Addr().String(),
+		MaxRetries: -1,
+	})
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return stub, client
+}
+
+func (s *redisStub) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.keys[key]
+
+	return value, ok
+}
+
+func (s *redisStub) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(r)
+
+		if err != nil {
+			return
+		}
+
+		reply := "+OK\r\n"
+
+		s.mu.Lock()
+
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			reply = "$-1\r\n"
+
+			if value, ok := s.keys[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			}
+		case "SET":
+			s.keys[args[1]] = args[2]
+		case "DEL":
+			deleted := 0
+
+			for _, key := range args[1:] {
+				if _, ok := s.keys[key]; ok {
+					delete(s.keys, key)
+					deleted++
+				}
+			}
+
+			reply = fmt.Sprintf(":%d\r\n", deleted)
+		}
+
+		s.mu.Unlock()
+
+		_, err = io.WriteString(conn, reply)
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readCommand read RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+
+	for i := range args {
+		line, err = r.ReadString('\n')
+
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+
+		_, err = io.ReadFull(r, buf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		args[i] = string(buf[:size])
+	}
+
+	return args, nil
+}
+
+// Goal types cached before requires_proof was added must not be read.
+func TestCacheSkipsUnversioned(t *testing.T) {
+	ctx := context.Background()
+	stub, client := newRedisStub(t)
+	db, err := sql.Open("goalTypesStub", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	m, err := NewModel(ModelConfig{Db: db, Cache: client})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Old entry has no requires_proof field.
+	stale := `{"id":1,"name":"Подъём","points":10}`
+
+	err = client.Set(ctx, "goal_type_1", stale, 0).Err()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Set(ctx, "goal_type_list", "["+stale+"]", 0).Err()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gType, err := m.Get(ctx, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !gType.RequiresProof {
+		t.Errorf("Get returned unversioned entry %+v", gType)
+	}
+
+	gTypes, err := m.List(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gTypes) != 1 || !gTypes[0].RequiresProof {
+		raw, _ := json.Marshal(gTypes)
+		t.Errorf("List returned unversioned entry %s", raw)
+	}
+
+	for _, key := range []string{"goal_type_1", "goal_type_list"} {
+		if _, ok := stub.get(key); ok {
+			t.Errorf("unversioned key %s is kept", key)
+		}
+	}
+
+	// Cached entries are read from versioned keys.
+	gType, err = m.Get(ctx, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !gType.RequiresProof {
+		t.Errorf("Get returned %+v from cache", gType)
+	}
+}
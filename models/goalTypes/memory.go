@@ -0,0 +1,111 @@
+package goalTypes
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory goal types storage.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int64
+	gTypes map[int64]GoalType
+}
+
+// NewMemory create new Memory with goal types.
+func NewMemory(gTypes ...*GoalType) *Memory {
+	m := &Memory{
+		gTypes: make(map[int64]GoalType),
+	}
+
+	for _, gType := range gTypes {
+		m.gTypes[gType.ID] = *gType
+
+		if gType.ID > m.lastID {
+			m.lastID = gType.ID
+		}
+	}
+
+	return m
+}
+
+// Get get goal type by id.
+func (m *Memory) Get(_ context.Context, id int64) (*GoalType, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	gType, ok := m.gTypes[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &gType, nil
+}
+
+// List get goal types.
+func (m *Memory) List(_ context.Context) ([]*GoalType, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var gTypes []*GoalType
+
+	for _, gType := range m.gTypes {
+		gType := gType
+		gTypes = append(gTypes, &gType)
+	}
+
+	sort.Slice(gTypes, func(i, j int) bool {
+		return gTypes[i].ID < gTypes[j].ID
+	})
+
+	return gTypes, nil
+}
+
+// Create create new goal type.
+func (m *Memory) Create(_ context.Context, gType *GoalType) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	stored := *gType
+	stored.ID = m.lastID
+	m.gTypes[stored.ID] = stored
+
+	return stored.ID, nil
+}
+
+// Update update goal type.
+func (m *Memory) Update(_ context.Context, gType *GoalType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.gTypes[gType.ID]; !ok {
+		return ErrNotFound
+	}
+
+	m.gTypes[gType.ID] = *gType
+
+	return nil
+}
+
+// Delete delete goal type by id.
+func (m *Memory) Delete(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.gTypes[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(m.gTypes, id)
+
+	return nil
+}
@@ -3,6 +3,17 @@ package goals
 import (
 	"context"
 	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound goal not found.
+	ErrNotFound = errors.New("goal not found")
+
+	// ErrInUse goal referenced by user goals.
+	ErrInUse = errors.New("goal in use")
 )
 
 var (
@@ -11,6 +22,33 @@ var (
 	Awaking10 = 3
 )
 
+// Repository type represent goals storage.
+type Repository interface {
+	// Create create new goal.
+	Create(ctx context.Context, goal *Goal) (int64, error)
+
+	// Get get goal by ID.
+	Get(ctx context.Context, id int64) (*Goal, error)
+
+	// Isset check goal is set.
+	Isset(ctx context.Context, id int64) (bool, error)
+
+	// List get goals.
+	List(ctx context.Context, gType int64) ([]*Goal, error)
+
+	// Page get goals page, all types if gType is 0.
+	Page(ctx context.Context, gType int64, limit, offset int) ([]*Goal, error)
+
+	// Count count goals, all types if gType is 0.
+	Count(ctx context.Context, gType int64) (int64, error)
+
+	// Update update goal.
+	Update(ctx context.Context, goal *Goal) error
+
+	// Delete delete goal by ID.
+	Delete(ctx context.Context, id int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db *sql.DB
@@ -119,6 +157,102 @@ func (m *Model) List(ctx context.Context, gType int64) ([]*Goal, error) {
 	return goals, nil
 }
 
+// Page get goals page, all types if gType is 0.
+func (m *Model) Page(ctx context.Context, gType int64, limit, offset int) ([]*Goal, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"id", "type","description"
+									FROM goals
+									WHERE $1 = 0 OR "type" = $1
+									ORDER BY "id"
+									LIMIT $2 OFFSET $3`, gType, limit, offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	goals := make([]*Goal, 0, limit)
+
+	for rows.Next() {
+		var goal Goal
+
+		err = rows.Scan(&goal.ID, &goal.Type, &goal.Description)
+
+		if err != nil {
+			return nil, err
+		}
+
+		goals = append(goals, &goal)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return goals, nil
+}
+
+// Count count goals, all types if gType is 0.
+func (m *Model) Count(ctx context.Context, gType int64) (int64, error) {
+	var count int64
+
+	err := m.db.QueryRowContext(ctx, `SELECT count(*) FROM goals
+								WHERE $1 = 0 OR "type" = $1`, gType).Scan(&count)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Update update goal.
+func (m *Model) Update(ctx context.Context, goal *Goal) error {
+	res, err := m.db.ExecContext(ctx, `UPDATE goals SET
+									"type" = $2, "description" = $3
+								WHERE "id" = $1`,
+		goal.ID, goal.Type, goal.Description)
+
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+// Delete delete goal by ID.
+func (m *Model) Delete(ctx context.Context, id int64) error {
+	res, err := m.db.ExecContext(ctx, `DELETE FROM goals
+								WHERE "id" = $1`, id)
+
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23503" {
+				return ErrInUse
+			}
+		}
+
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 func (g *Goal) GetItem() interface{} {
 	return g.ID
 }
@@ -0,0 +1,169 @@
+package goals
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory goals storage.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int64
+	goals  map[int64]Goal
+}
+
+// NewMemory create new Memory with goals.
+func NewMemory(goals ...*Goal) *Memory {
+	m := &Memory{
+		goals: make(map[int64]Goal),
+	}
+
+	for _, goal := range goals {
+		m.goals[goal.ID] = *goal
+
+		if goal.ID > m.lastID {
+			m.lastID = goal.ID
+		}
+	}
+
+	return m
+}
+
+// Create create new goal.
+func (m *Memory) Create(_ context.Context, goal *Goal) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	stored := *goal
+	stored.ID = m.lastID
+	m.goals[stored.ID] = stored
+
+	return stored.ID, nil
+}
+
+// Get get goal by ID.
+func (m *Memory) Get(_ context.Context, id int64) (*Goal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	goal, ok := m.goals[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &goal, nil
+}
+
+// Isset check goal is set.
+func (m *Memory) Isset(_ context.Context, id int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.goals[id]
+
+	return ok, nil
+}
+
+// List get goals.
+func (m *Memory) List(_ context.Context, gType int64) ([]*Goal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var goals []*Goal
+
+	for _, goal := range m.goals {
+		if goal.Type != gType {
+			continue
+		}
+
+		goal := goal
+		goals = append(goals, &goal)
+	}
+
+	sort.Slice(goals, func(i, j int) bool {
+		return goals[i].ID < goals[j].ID
+	})
+
+	return goals, nil
+}
+
+// Page get goals page, all types if gType is 0.
+func (m *Memory) Page(_ context.Context, gType int64, limit, offset int) ([]*Goal, error) {
+	goals := m.filter(gType)
+
+	if offset >= len(goals) {
+		return []*Goal{}, nil
+	}
+
+	goals = goals[offset:]
+
+	if limit < len(goals) {
+		goals = goals[:limit]
+	}
+
+	return goals, nil
+}
+
+// Count count goals, all types if gType is 0.
+func (m *Memory) Count(_ context.Context, gType int64) (int64, error) {
+	return int64(len(m.filter(gType))), nil
+}
+
+// Update update goal.
+func (m *Memory) Update(_ context.Context, goal *Goal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.goals[goal.ID]; !ok {
+		return ErrNotFound
+	}
+
+	m.goals[goal.ID] = *goal
+
+	return nil
+}
+
+// Delete delete goal by ID.
+func (m *Memory) Delete(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.goals[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(m.goals, id)
+
+	return nil
+}
+
+func (m *Memory) filter(gType int64) []*Goal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	goals := make([]*Goal, 0, len(m.goals))
+
+	for _, goal := range m.goals {
+		if gType != 0 && goal.Type != gType {
+			continue
+		}
+
+		goal := goal
+		goals = append(goals, &goal)
+	}
+
+	sort.Slice(goals, func(i, j int) bool {
+		return goals[i].ID < goals[j].ID
+	})
+
+	return goals
+}
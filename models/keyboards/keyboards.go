@@ -5,6 +5,12 @@ import (
 	"database/sql"
 )
 
+// Repository type represent keyboards storage.
+type Repository interface {
+	// GetKeyboard get keyboard by service and name.
+	GetKeyboard(ctx context.Context, service string, keyboard string) (string, error)
+}
+
 // Model type represent model.
 type Model struct {
 	db *sql.DB
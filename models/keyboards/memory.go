@@ -0,0 +1,49 @@
+package keyboards
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory keyboards storage.
+type Memory struct {
+	mu        sync.RWMutex
+	keyboards map[string]string
+}
+
+// NewMemory create new Memory with keyboards.
+func NewMemory(keyboards ...*Keyboard) *Memory {
+	m := &Memory{
+		keyboards: make(map[string]string),
+	}
+
+	for _, kb := range keyboards {
+		m.keyboards[m.key(kb.Service, kb.Name)] = kb.Keyboard
+	}
+
+	return m
+}
+
+// GetKeyboard get keyboard by service and name.
+func (m *Memory) GetKeyboard(_ context.Context, service string, keyboard string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	kb, ok := m.keyboards[m.key(service, keyboard)]
+
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return kb, nil
+}
+
+func (m *Memory) key(service string, keyboard string) string {
+	return service + "/" + keyboard
+}
@@ -0,0 +1,172 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory outbox storage.
+type Memory struct {
+	mu       sync.RWMutex
+	lastID   int64
+	msgs     map[int64]Message
+	keys     map[string]int64
+	attempts map[int64][]Attempt
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		msgs:     make(map[int64]Message),
+		keys:     make(map[string]int64),
+		attempts: make(map[int64][]Attempt),
+	}
+}
+
+// Create create new pending message, ErrExists is returned
+// when message with same key was created before.
+func (m *Memory) Create(_ context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.keys[msg.Key]; ok {
+		return ErrExists
+	}
+
+	createdAt := time.Now()
+	m.lastID++
+	msg.ID = m.lastID
+	msg.Status = StatusPending
+	msg.CreatedAt = &createdAt
+	m.msgs[msg.ID] = *msg
+	m.keys[msg.Key] = msg.ID
+
+	return nil
+}
+
+// Get get message by ID.
+func (m *Memory) Get(_ context.Context, id int64) (*Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	msg, ok := m.msgs[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &msg, nil
+}
+
+// ListDue get pending messages which next attempt is before date.
+func (m *Memory) ListDue(_ context.Context, date time.Time, limit int) ([]*Message, error) {
+	msgs := m.filter(func(msg *Message) bool {
+		return msg.Status == StatusPending && !msg.NextAttempt.After(date)
+	})
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].NextAttempt.Before(msgs[j].NextAttempt)
+	})
+
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+
+	return msgs, nil
+}
+
+// List get messages page by filter, newest first.
+func (m *Memory) List(_ context.Context, filter Filter, limit, offset int) ([]*Message, error) {
+	msgs := m.filter(filter.match)
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].ID > msgs[j].ID
+	})
+
+	if offset >= len(msgs) {
+		return make([]*Message, 0), nil
+	}
+
+	msgs = msgs[offset:]
+
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+
+	return msgs, nil
+}
+
+// Count count messages by filter.
+func (m *Memory) Count(_ context.Context, filter Filter) (int64, error) {
+	return int64(len(m.filter(filter.match))), nil
+}
+
+// SaveAttempt update message after delivery attempt and log attempt.
+func (m *Memory) SaveAttempt(_ context.Context, msg *Message, attempt *Attempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.msgs[msg.ID]
+
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	stored.Status = msg.Status
+	stored.Attempts = msg.Attempts
+	stored.NextAttempt = msg.NextAttempt
+	stored.LastError = msg.LastError
+	stored.SentAt = msg.SentAt
+	m.msgs[msg.ID] = stored
+
+	createdAt := time.Now()
+	attempt.CreatedAt = &createdAt
+	m.attempts[msg.ID] = append(m.attempts[msg.ID], *attempt)
+
+	return nil
+}
+
+// ListAttempts get message delivery attempts.
+func (m *Memory) ListAttempts(_ context.Context, messageID int64) ([]*Attempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	attempts := make([]*Attempt, 0, len(m.attempts[messageID]))
+
+	for _, attempt := range m.attempts[messageID] {
+		attempt := attempt
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, nil
+}
+
+func (m *Memory) filter(match func(msg *Message) bool) []*Message {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	msgs := make([]*Message, 0)
+
+	for _, msg := range m.msgs {
+		msg := msg
+
+		if match(&msg) {
+			msgs = append(msgs, &msg)
+		}
+	}
+
+	return msgs
+}
+
+func (f Filter) match(msg *Message) bool {
+	return (f.Status == "" || msg.Status == f.Status) &&
+		(f.UserID == 0 || msg.UserID == f.UserID)
+}
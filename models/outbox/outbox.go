@@ -0,0 +1,316 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Message statuses.
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+var (
+	// ErrExists message with same idempotency key exists.
+	ErrExists = errors.New("outbox message exists")
+)
+
+// Repository type represent notifications outbox storage.
+type Repository interface {
+	// Create create new pending message, ErrExists is returned
+	// when message with same key was created before.
+	Create(ctx context.Context, msg *Message) error
+
+	// Get get message by ID.
+	Get(ctx context.Context, id int64) (*Message, error)
+
+	// ListDue get pending messages which next attempt is before date.
+	ListDue(ctx context.Context, date time.Time, limit int) ([]*Message, error)
+
+	// List get messages page by filter, newest first.
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Message, error)
+
+	// Count count messages by filter.
+	Count(ctx context.Context, filter Filter) (int64, error)
+
+	// SaveAttempt update message after delivery attempt and log attempt.
+	SaveAttempt(ctx context.Context, msg *Message, attempt *Attempt) error
+
+	// ListAttempts get message delivery attempts.
+	ListAttempts(ctx context.Context, messageID int64) ([]*Attempt, error)
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Message type represent notification waiting for delivery.
+type Message struct {
+	ID          int64                  `json:"id"`
+	Key         string                 `json:"key"`
+	UserID      int64                  `json:"user_id"`
+	Code        string                 `json:"code"`
+	Text        string                 `json:"text"`
+	Params      map[string]interface{} `json:"params"`
+	Status      string                 `json:"status"`
+	Attempts    int                    `json:"attempts"`
+	NextAttempt time.Time              `json:"next_attempt"`
+	LastError   string                 `json:"last_error"`
+	CreatedAt   *time.Time             `json:"created_at"`
+	SentAt      *time.Time             `json:"sent_at"`
+}
+
+// Attempt type represent delivery log record.
+type Attempt struct {
+	MessageID int64      `json:"message_id"`
+	Number    int        `json:"number"`
+	Error     string     `json:"error"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// Filter messages list filter, zero fields are not applied.
+type Filter struct {
+	Status string
+	UserID int64
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Create create new pending message, ErrExists is returned
+// when message with same key was created before.
+func (m *Model) Create(ctx context.Context, msg *Message) error {
+	params, err := json.Marshal(msg.Params)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.db.QueryRowContext(ctx, `INSERT INTO outbox
+									("key", "user_id", "code", "text",
+									 "params", "next_attempt")
+								VALUES ($1, $2, $3, $4, $5, $6)
+								RETURNING "id", "status", "created_at"`,
+		msg.Key, msg.UserID, msg.Code, msg.Text, params, msg.NextAttempt,
+	).Scan(&msg.ID, &msg.Status, &msg.CreatedAt)
+
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" {
+				return ErrExists
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Get get message by ID.
+func (m *Model) Get(ctx context.Context, id int64) (*Message, error) {
+	row := m.db.QueryRowContext(ctx, `SELECT
+									"id", "key", "user_id", "code", "text",
+									"params", "status", "attempts",
+									"next_attempt", "last_error",
+									"created_at", "sent_at"
+									FROM outbox
+								WHERE "id" = $1`, id)
+
+	return m.scan(row)
+}
+
+// ListDue get pending messages which next attempt is before date.
+func (m *Model) ListDue(ctx context.Context, date time.Time, limit int) ([]*Message, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"id", "key", "user_id", "code", "text",
+									"params", "status", "attempts",
+									"next_attempt", "last_error",
+									"created_at", "sent_at"
+									FROM outbox
+								WHERE "status" = $1 AND "next_attempt" <= $2
+								ORDER BY "next_attempt"
+								LIMIT $3`, StatusPending, date, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m.list(rows)
+}
+
+// List get messages page by filter, newest first.
+func (m *Model) List(ctx context.Context, filter Filter, limit, offset int) ([]*Message, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"id", "key", "user_id", "code", "text",
+									"params", "status", "attempts",
+									"next_attempt", "last_error",
+									"created_at", "sent_at"
+									FROM outbox
+								WHERE ($1::text = '' OR "status" = $1)
+								AND ($2::bigint = 0 OR "user_id" = $2)
+								ORDER BY "id" DESC
+								LIMIT $3 OFFSET $4`,
+		filter.Status, filter.UserID, limit, offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m.list(rows)
+}
+
+// Count count messages by filter.
+func (m *Model) Count(ctx context.Context, filter Filter) (int64, error) {
+	var count int64
+
+	err := m.db.QueryRowContext(ctx, `SELECT count(*)
+									FROM outbox
+								WHERE ($1::text = '' OR "status" = $1)
+								AND ($2::bigint = 0 OR "user_id" = $2)`,
+		filter.Status, filter.UserID).Scan(&count)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SaveAttempt update message after delivery attempt and log attempt.
+func (m *Model) SaveAttempt(ctx context.Context, msg *Message, attempt *Attempt) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE outbox SET
+									"status" = $2, "attempts" = $3,
+									"next_attempt" = $4, "last_error" = $5,
+									"sent_at" = $6
+								WHERE "id" = $1`,
+		msg.ID, msg.Status, msg.Attempts, msg.NextAttempt,
+		msg.LastError, msg.SentAt)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO outbox_attempts
+									("message_id", "number", "error")
+								VALUES ($1, $2, $3)
+								RETURNING "created_at"`,
+		attempt.MessageID, attempt.Number, attempt.Error,
+	).Scan(&attempt.CreatedAt)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListAttempts get message delivery attempts.
+func (m *Model) ListAttempts(ctx context.Context, messageID int64) ([]*Attempt, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"message_id", "number", "error", "created_at"
+									FROM outbox_attempts
+								WHERE "message_id" = $1
+								ORDER BY "number"`, messageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attempts := make([]*Attempt, 0)
+
+	for rows.Next() {
+		var attempt Attempt
+
+		err = rows.Scan(&attempt.MessageID, &attempt.Number, &attempt.Error,
+			&attempt.CreatedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, &attempt)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return attempts, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (m *Model) scan(row scanner) (*Message, error) {
+	var msg Message
+	var params []byte
+
+	err := row.Scan(&msg.ID, &msg.Key, &msg.UserID, &msg.Code, &msg.Text,
+		&params, &msg.Status, &msg.Attempts, &msg.NextAttempt, &msg.LastError,
+		&msg.CreatedAt, &msg.SentAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(params, &msg.Params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+func (m *Model) list(rows *sql.Rows) ([]*Message, error) {
+	defer rows.Close()
+
+	msgs := make([]*Message, 0)
+
+	for rows.Next() {
+		msg, err := m.scan(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return msgs, nil
+}
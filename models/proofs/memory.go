@@ -0,0 +1,58 @@
+package proofs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory proofs storage.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int64
+	proofs []Proof
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Create create new proof.
+func (m *Memory) Create(_ context.Context, proof *Proof) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.lastID++
+	proof.ID = m.lastID
+	stored := *proof
+	stored.CreatedAt = &now
+	m.proofs = append(m.proofs, stored)
+
+	return nil
+}
+
+// ListByUserGoal get proofs of user goal.
+func (m *Memory) ListByUserGoal(_ context.Context, uGoalID int64) ([]*Proof, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Proof
+
+	for _, proof := range m.proofs {
+		if proof.UserGoalID != uGoalID {
+			continue
+		}
+
+		proof := proof
+		list = append(list, &proof)
+	}
+
+	return list, nil
+}
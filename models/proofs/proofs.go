@@ -0,0 +1,123 @@
+package proofs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const (
+	KindAppeal     = "appeal"
+	KindCompletion = "completion"
+)
+
+// Repository type represent proofs storage.
+type Repository interface {
+	// Create create new proof.
+	Create(ctx context.Context, proof *Proof) error
+
+	// ListByUserGoal get proofs of user goal.
+	ListByUserGoal(ctx context.Context, uGoalID int64) ([]*Proof, error)
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Proof type represent user goal proof.
+type Proof struct {
+	ID          int64         `json:"id"`
+	UserGoalID  int64         `json:"user_goal_id"`
+	Kind        string        `json:"kind"`
+	Text        string        `json:"text"`
+	Attachments []*Attachment `json:"attachments"`
+	CreatedAt   *time.Time    `json:"created_at"`
+}
+
+// Attachment type represent proof file stored by messaging service.
+type Attachment struct {
+	Service string `json:"service"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Create create new proof.
+func (m *Model) Create(ctx context.Context, proof *Proof) error {
+	attachments, err := json.Marshal(proof.Attachments)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.db.QueryRowContext(ctx, `INSERT INTO proofs
+									("user_goal_id", "kind", "text", "attachments")
+								VALUES ($1, $2, $3, $4)
+								RETURNING "id"`,
+		proof.UserGoalID, proof.Kind, proof.Text, attachments).Scan(&proof.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListByUserGoal get proofs of user goal.
+func (m *Model) ListByUserGoal(ctx context.Context, uGoalID int64) ([]*Proof, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"id", "user_goal_id", "kind", "text",
+									"attachments", "created_at"
+									FROM proofs
+									WHERE "user_goal_id" = $1
+									ORDER BY "id"`, uGoalID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list []*Proof
+
+	for rows.Next() {
+		var proof Proof
+		var attachments []byte
+
+		err = rows.Scan(&proof.ID, &proof.UserGoalID, &proof.Kind, &proof.Text,
+			&attachments, &proof.CreatedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(attachments, &proof.Attachments)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, &proof)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return list, nil
+}
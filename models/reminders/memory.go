@@ -0,0 +1,106 @@
+package reminders
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory reminders storage.
+type Memory struct {
+	mu        sync.RWMutex
+	reminders map[string]Reminder
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		reminders: make(map[string]Reminder),
+	}
+}
+
+// Set set reminder due time.
+func (m *Memory) Set(_ context.Context, reminder *Reminder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reminders[member(reminder.UserID, reminder.Code)] = *reminder
+
+	return nil
+}
+
+// Get get user reminder, nil if it is not set.
+func (m *Memory) Get(_ context.Context, userID int64, code string) (*Reminder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	reminder, ok := m.reminders[member(userID, code)]
+
+	if !ok {
+		return nil, nil
+	}
+
+	return &reminder, nil
+}
+
+// ListDue get reminders due before now, earliest first.
+func (m *Memory) ListDue(_ context.Context, now time.Time, limit int) ([]*Reminder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Reminder
+
+	for _, reminder := range m.reminders {
+		if !reminder.Due.After(now) {
+			reminder := reminder
+			list = append(list, &reminder)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Due.Before(list[j].Due)
+	})
+
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	return list, nil
+}
+
+// Remove remove reminder, false is returned when it
+// was removed before, e.g. claimed by other instance.
+func (m *Memory) Remove(_ context.Context, userID int64, code string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := member(userID, code)
+
+	if _, ok := m.reminders[k]; !ok {
+		return false, nil
+	}
+
+	delete(m.reminders, k)
+
+	return true, nil
+}
+
+// DeleteByUser delete all user reminders.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, reminder := range m.reminders {
+		if reminder.UserID == userID {
+			delete(m.reminders, k)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// key sorted set of reminders scored by due unix time.
+const key = "reminders"
+
+// Repository type represent reminders storage.
+type Repository interface {
+	// Set set reminder due time.
+	Set(ctx context.Context, reminder *Reminder) error
+
+	// Get get user reminder, nil if it is not set.
+	Get(ctx context.Context, userID int64, code string) (*Reminder, error)
+
+	// ListDue get reminders due before now, earliest first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Reminder, error)
+
+	// Remove remove reminder, false is returned when it
+	// was removed before, e.g. claimed by other instance.
+	Remove(ctx context.Context, userID int64, code string) (bool, error)
+
+	// DeleteByUser delete all user reminders.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+// Model type represent model.
+type Model struct {
+	cache *redis.Client
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Cache *redis.Client
+}
+
+// Reminder type represent user reminder.
+type Reminder struct {
+	UserID int64     `json:"user_id"`
+	Code   string    `json:"code"`
+	Due    time.Time `json:"due"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		cache: config.Cache,
+	}
+
+	return m, nil
+}
+
+// Set set reminder due time.
+func (m *Model) Set(ctx context.Context, reminder *Reminder) error {
+	err := m.cache.ZAdd(ctx, key, &redis.Z{
+		Score:  float64(reminder.Due.Unix()),
+		Member: member(reminder.UserID, reminder.Code),
+	}).Err()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get get user reminder, nil if it is not set.
+func (m *Model) Get(ctx context.Context, userID int64, code string) (*Reminder, error) {
+	score, err := m.cache.ZScore(ctx, key, member(userID, code)).Result()
+
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &Reminder{
+		UserID: userID,
+		Code:   code,
+		Due:    time.Unix(int64(score), 0),
+	}, nil
+}
+
+// ListDue get reminders due before now, earliest first.
+func (m *Model) ListDue(ctx context.Context, now time.Time, limit int) ([]*Reminder, error) {
+	items, err := m.cache.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Reminder
+
+	for _, item := range items {
+		reminder, err := parse(item)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, reminder)
+	}
+
+	return list, nil
+}
+
+// Remove remove reminder, false is returned when it
+// was removed before, e.g. claimed by other instance.
+func (m *Model) Remove(ctx context.Context, userID int64, code string) (bool, error) {
+	removed, err := m.cache.ZRem(ctx, key, member(userID, code)).Result()
+
+	if err != nil {
+		return false, err
+	}
+
+	return removed > 0, nil
+}
+
+// DeleteByUser delete all user reminders.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	iter := m.cache.ZScan(ctx, key, 0, member(userID, "*"), 0).Iterator()
+
+	var members []interface{}
+
+	// ZSCAN returns member and score pairs.
+	for i := 0; iter.Next(ctx); i++ {
+		if i%2 == 0 {
+			members = append(members, iter.Val())
+		}
+	}
+
+	if iter.Err() != nil {
+		return iter.Err()
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	err := m.cache.ZRem(ctx, key, members...).Err()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func member(userID int64, code string) string {
+	return fmt.Sprintf("%d:%s", userID, code)
+}
+
+func parse(item redis.Z) (*Reminder, error) {
+	raw, _ := item.Member.(string)
+	parts := strings.SplitN(raw, ":", 2)
+
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid reminder %q", raw)
+	}
+
+	userID, err := strconv.ParseInt(parts[0], 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reminder{
+		UserID: userID,
+		Code:   parts[1],
+		Due:    time.Unix(int64(item.Score), 0),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package reviews
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory reviews storage.
+type Memory struct {
+	mu      sync.RWMutex
+	reviews map[int64]Review
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		reviews: make(map[int64]Review),
+	}
+}
+
+// Create create new review.
+func (m *Memory) Create(_ context.Context, review *Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.reviews[review.UserGoalID]; !ok {
+		m.reviews[review.UserGoalID] = *review
+	}
+
+	return nil
+}
+
+// Get get review by user goal ID.
+func (m *Memory) Get(_ context.Context, uGoalID int64) (*Review, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	review, ok := m.reviews[uGoalID]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &review, nil
+}
+
+// Update update review.
+func (m *Memory) Update(_ context.Context, review *Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.reviews[review.UserGoalID]; ok {
+		m.reviews[review.UserGoalID] = *review
+	}
+
+	return nil
+}
+
+// ListByStatus get reviews by status.
+func (m *Memory) ListByStatus(_ context.Context, status string) ([]*Review, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Review
+
+	for _, review := range m.reviews {
+		if review.Status != status {
+			continue
+		}
+
+		review := review
+		list = append(list, &review)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Deadline.Before(list[j].Deadline)
+	})
+
+	return list, nil
+}
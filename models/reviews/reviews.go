@@ -0,0 +1,144 @@
+package reviews
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Repository type represent reviews storage.
+type Repository interface {
+	// Create create new review.
+	Create(ctx context.Context, review *Review) error
+
+	// Get get review by user goal ID.
+	Get(ctx context.Context, uGoalID int64) (*Review, error)
+
+	// Update update review.
+	Update(ctx context.Context, review *Review) error
+
+	// ListByStatus get reviews by status.
+	ListByStatus(ctx context.Context, status string) ([]*Review, error)
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Review type represent peer review of user goal.
+// Round is 1 for first review and 2 after appeal.
+type Review struct {
+	UserGoalID int64      `json:"user_goal_id"`
+	Status     string     `json:"status"`
+	Round      int64      `json:"round"`
+	Deadline   time.Time  `json:"deadline"`
+	DecidedAt  *time.Time `json:"decided_at"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Create create new review.
+func (m *Model) Create(ctx context.Context, review *Review) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO reviews
+									("user_goal_id", "status", "round", "deadline")
+								VALUES ($1, $2, $3, $4)
+								ON CONFLICT DO NOTHING`,
+		review.UserGoalID, review.Status, review.Round, review.Deadline)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get get review by user goal ID.
+func (m *Model) Get(ctx context.Context, uGoalID int64) (*Review, error) {
+	var review Review
+
+	err := m.db.QueryRowContext(ctx, `SELECT
+									"user_goal_id", "status", "round",
+									"deadline", "decided_at"
+									FROM reviews
+								WHERE "user_goal_id" = $1`, uGoalID).
+		Scan(&review.UserGoalID, &review.Status, &review.Round,
+			&review.Deadline, &review.DecidedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// Update update review.
+func (m *Model) Update(ctx context.Context, review *Review) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE reviews SET
+									"status" = $2, "round" = $3,
+									"deadline" = $4, "decided_at" = $5
+								WHERE "user_goal_id" = $1`,
+		review.UserGoalID, review.Status, review.Round,
+		review.Deadline, review.DecidedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListByStatus get reviews by status.
+func (m *Model) ListByStatus(ctx context.Context, status string) ([]*Review, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"user_goal_id", "status", "round",
+									"deadline", "decided_at"
+									FROM reviews
+									WHERE "status" = $1
+									ORDER BY "deadline"`, status)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list []*Review
+
+	for rows.Next() {
+		var review Review
+
+		err = rows.Scan(&review.UserGoalID, &review.Status, &review.Round,
+			&review.Deadline, &review.DecidedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, &review)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return list, nil
+}
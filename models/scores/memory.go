@@ -0,0 +1,134 @@
+package scores
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory scores storage.
+type Memory struct {
+	mu      sync.RWMutex
+	goals   map[int64]Score
+	periods map[periodKey]int64
+	erased  map[periodKey]erasedScore
+}
+
+// erasedScore aggregate of erased users, keyed without user.
+type erasedScore struct {
+	users  int64
+	points int64
+}
+
+type periodKey struct {
+	userID int64
+	period string
+	start  time.Time
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		goals:   make(map[int64]Score),
+		periods: make(map[periodKey]int64),
+		erased:  make(map[periodKey]erasedScore),
+	}
+}
+
+// Set set user goal score and update period scores.
+func (m *Memory) Set(_ context.Context, score *Score) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delta := score.Points - m.goals[score.UserGoalID].Points
+	m.goals[score.UserGoalID] = *score
+
+	for _, period := range Periods {
+		m.periods[periodKey{
+			userID: score.UserID,
+			period: period,
+			start:  PeriodStart(period, score.Date),
+		}] += delta
+	}
+
+	return nil
+}
+
+// Get get user score for period containing date.
+func (m *Memory) Get(_ context.Context, userID int64, period string, date time.Time) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.periods[periodKey{
+		userID: userID,
+		period: period,
+		start:  PeriodStart(period, date),
+	}], nil
+}
+
+// Leaderboard get best scores for period containing date.
+func (m *Memory) Leaderboard(_ context.Context, period string, date time.Time, limit int) ([]*Entry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	start := PeriodStart(period, date)
+
+	var entries []*Entry
+
+	for key, points := range m.periods {
+		if key.period != period || !key.start.Equal(start) || points <= 0 {
+			continue
+		}
+
+		entries = append(entries, &Entry{UserID: key.userID, Points: points})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Points != entries[j].Points {
+			return entries[i].Points > entries[j].Points
+		}
+
+		return entries[i].UserID < entries[j].UserID
+	})
+
+	if limit < len(entries) {
+		entries = entries[:limit]
+	}
+
+	return entries, nil
+}
+
+// Erase delete user scores, period points are kept
+// in anonymous aggregates of erased users.
+func (m *Memory) Erase(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, points := range m.periods {
+		if key.userID != userID {
+			continue
+		}
+
+		erasedKey := periodKey{period: key.period, start: key.start}
+		erased := m.erased[erasedKey]
+		erased.users++
+		erased.points += points
+		m.erased[erasedKey] = erased
+
+		delete(m.periods, key)
+	}
+
+	for id, score := range m.goals {
+		if score.UserID == userID {
+			delete(m.goals, id)
+		}
+	}
+
+	return nil
+}
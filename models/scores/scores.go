@@ -0,0 +1,237 @@
+package scores
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Periods score aggregation periods.
+var Periods = []string{PeriodDay, PeriodWeek, PeriodMonth}
+
+// Repository type represent scores storage.
+type Repository interface {
+	// Set set user goal score and update period scores.
+	Set(ctx context.Context, score *Score) error
+
+	// Get get user score for period containing date.
+	Get(ctx context.Context, userID int64, period string, date time.Time) (int64, error)
+
+	// Leaderboard get best scores for period containing date.
+	Leaderboard(ctx context.Context, period string, date time.Time, limit int) ([]*Entry, error)
+
+	// Erase delete user scores, period points are kept
+	// in anonymous aggregates of erased users.
+	Erase(ctx context.Context, userID int64) error
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Score type represent user goal score.
+type Score struct {
+	UserGoalID int64     `json:"user_goal_id"`
+	UserID     int64     `json:"user_id"`
+	Date       time.Time `json:"date"`
+	Points     int64     `json:"points"`
+}
+
+// Entry type represent leaderboard entry.
+type Entry struct {
+	UserID int64 `json:"user_id"`
+	Points int64 `json:"points"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Set set user goal score and update period scores.
+func (m *Model) Set(ctx context.Context, score *Score) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	var prev int64
+
+	err = tx.QueryRowContext(ctx, `SELECT "points" FROM goal_scores
+								WHERE "user_goal_id" = $1
+								FOR UPDATE`, score.UserGoalID).Scan(&prev)
+
+	if err != nil && err != sql.ErrNoRows {
+		_ = tx.Rollback()
+		return err
+	}
+
+	delta := score.Points - prev
+
+	if delta == 0 {
+		return tx.Rollback()
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO goal_scores
+									("user_goal_id", "user_id", "date", "points")
+								VALUES ($1, $2, $3, $4)
+								ON CONFLICT ("user_goal_id")
+								DO UPDATE SET "points" = EXCLUDED."points"`,
+		score.UserGoalID, score.UserID, score.Date, score.Points)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, period := range Periods {
+		_, err = tx.ExecContext(ctx, `INSERT INTO scores
+									("user_id", "period", "start", "points")
+								VALUES ($1, $2, $3, $4)
+								ON CONFLICT ("user_id", "period", "start")
+								DO UPDATE SET "points" = scores."points" + EXCLUDED."points"`,
+			score.UserID, period, PeriodStart(period, score.Date), delta)
+
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get get user score for period containing date.
+func (m *Model) Get(ctx context.Context, userID int64, period string, date time.Time) (int64, error) {
+	var points int64
+
+	err := m.db.QueryRowContext(ctx, `SELECT "points" FROM scores
+								WHERE "user_id" = $1
+								AND "period" = $2 AND "start" = $3`,
+		userID, period, PeriodStart(period, date)).Scan(&points)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return points, nil
+}
+
+// Leaderboard get best scores for period containing date.
+func (m *Model) Leaderboard(ctx context.Context, period string, date time.Time, limit int) ([]*Entry, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT "user_id", "points"
+									FROM scores
+									WHERE "period" = $1 AND "start" = $2
+									AND "points" > 0
+									ORDER BY "points" DESC, "user_id"
+									LIMIT $3`,
+		period, PeriodStart(period, date), limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var entries []*Entry
+
+	for rows.Next() {
+		var entry Entry
+
+		err = rows.Scan(&entry.UserID, &entry.Points)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return entries, nil
+}
+
+// PeriodStart get first day of period containing date.
+// Weeks start on Monday.
+func PeriodStart(period string, date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case PeriodWeek:
+		weekday := (int(day.Weekday()) + 6) % 7
+
+		return day.AddDate(0, 0, -weekday)
+	case PeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+
+	return day
+}
+
+// Erase delete user scores, period points are kept
+// in anonymous aggregates of erased users.
+func (m *Model) Erase(ctx context.Context, userID int64) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO erased_scores
+									("period", "start", "users", "points")
+								SELECT "period", "start", 1, "points"
+									FROM scores
+								WHERE "user_id" = $1
+								ON CONFLICT ("period", "start")
+								DO UPDATE SET
+									"users" = erased_scores."users" + 1,
+									"points" = erased_scores."points" + EXCLUDED."points"`,
+		userID)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM goal_scores
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM scores
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,91 @@
+package streaks
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+type key struct {
+	userID int64
+	gType  int64
+}
+
+// Memory type represent in-memory streaks storage.
+type Memory struct {
+	mu      sync.RWMutex
+	streaks map[key]Streak
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		streaks: make(map[key]Streak),
+	}
+}
+
+// Get get streak of user goal type.
+func (m *Memory) Get(_ context.Context, userID int64, gType int64) (*Streak, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	streak, ok := m.streaks[key{userID, gType}]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &streak, nil
+}
+
+// Set create or replace streak.
+func (m *Memory) Set(_ context.Context, streak *Streak) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.streaks[key{streak.UserID, streak.Type}] = *streak
+
+	return nil
+}
+
+// ListByUser get user streaks.
+func (m *Memory) ListByUser(_ context.Context, userID int64) ([]*Streak, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Streak
+
+	for k, streak := range m.streaks {
+		streak := streak
+
+		if k.userID == userID {
+			list = append(list, &streak)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Type < list[j].Type
+	})
+
+	return list, nil
+}
+
+// DeleteByUser delete user streaks.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k := range m.streaks {
+		if k.userID == userID {
+			delete(m.streaks, k)
+		}
+	}
+
+	return nil
+}
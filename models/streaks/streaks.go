@@ -0,0 +1,159 @@
+package streaks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Repository type represent streaks storage.
+type Repository interface {
+	// Get get streak of user goal type.
+	Get(ctx context.Context, userID int64, gType int64) (*Streak, error)
+
+	// Set create or replace streak.
+	Set(ctx context.Context, streak *Streak) error
+
+	// ListByUser get user streaks.
+	ListByUser(ctx context.Context, userID int64) ([]*Streak, error)
+
+	// DeleteByUser delete user streaks.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Streak type represent consecutive completed days of user goal type.
+// LastDate is the last finished day, BrokenAt is the day last streak was broken.
+type Streak struct {
+	UserID   int64      `json:"user_id"`
+	Type     int64      `json:"type"`
+	Current  int64      `json:"current"`
+	Best     int64      `json:"best"`
+	LastDate *time.Time `json:"last_date"`
+	BrokenAt *time.Time `json:"broken_at"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Get get streak of user goal type.
+func (m *Model) Get(ctx context.Context, userID int64, gType int64) (*Streak, error) {
+	var streak Streak
+
+	err := m.db.QueryRowContext(ctx, `SELECT
+									"user_id", "type", "current", "best",
+									"last_date", "broken_at"
+									FROM streaks
+								WHERE "user_id" = $1 AND "type" = $2`,
+		userID, gType).
+		Scan(&streak.UserID, &streak.Type, &streak.Current, &streak.Best,
+			&streak.LastDate, &streak.BrokenAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &streak, nil
+}
+
+// Set create or replace streak.
+func (m *Model) Set(ctx context.Context, streak *Streak) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO streaks
+									("user_id", "type", "current", "best",
+									"last_date", "broken_at")
+								VALUES ($1, $2, $3, $4, $5, $6)
+								ON CONFLICT ("user_id", "type") DO UPDATE SET
+									"current" = EXCLUDED."current",
+									"best" = EXCLUDED."best",
+									"last_date" = EXCLUDED."last_date",
+									"broken_at" = EXCLUDED."broken_at"`,
+		streak.UserID, streak.Type, streak.Current, streak.Best,
+		streak.LastDate, streak.BrokenAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Actual get current streak on date, streak is lost
+// when day before date is not finished yet.
+func (s *Streak) Actual(date time.Time) int64 {
+	if s.LastDate == nil {
+		return 0
+	}
+
+	yesterday := time.Date(date.Year(), date.Month(), date.Day()-1,
+		0, 0, 0, 0, time.UTC)
+
+	if s.LastDate.Before(yesterday) {
+		return 0
+	}
+
+	return s.Current
+}
+
+// ListByUser get user streaks.
+func (m *Model) ListByUser(ctx context.Context, userID int64) ([]*Streak, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"user_id", "type", "current", "best",
+									"last_date", "broken_at"
+									FROM streaks
+								WHERE "user_id" = $1
+								ORDER BY "type"`, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list []*Streak
+
+	for rows.Next() {
+		var streak Streak
+
+		err = rows.Scan(&streak.UserID, &streak.Type, &streak.Current,
+			&streak.Best, &streak.LastDate, &streak.BrokenAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, &streak)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return list, nil
+}
+
+// DeleteByUser delete user streaks.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM streaks
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package templates
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory goal templates storage.
+type Memory struct {
+	mu        sync.RWMutex
+	lastID    int64
+	templates map[int64]Template
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		templates: make(map[int64]Template),
+	}
+}
+
+// Create create new template.
+func (m *Memory) Create(_ context.Context, tpl *Template) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	tpl.ID = m.lastID
+	m.templates[tpl.ID] = *tpl
+
+	return nil
+}
+
+// Get get template by ID.
+func (m *Memory) Get(_ context.Context, id int64) (*Template, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tpl, ok := m.templates[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &tpl, nil
+}
+
+// Update update template.
+func (m *Memory) Update(_ context.Context, tpl *Template) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.templates[tpl.ID]; ok {
+		m.templates[tpl.ID] = *tpl
+	}
+
+	return nil
+}
+
+// Delete delete template by ID.
+func (m *Memory) Delete(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.templates, id)
+
+	return nil
+}
+
+// DeleteByUser delete user templates.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, tpl := range m.templates {
+		if tpl.UserID == userID {
+			delete(m.templates, id)
+		}
+	}
+
+	return nil
+}
+
+// ListByUser get user templates.
+func (m *Memory) ListByUser(_ context.Context, userID int64) ([]*Template, error) {
+	return m.filter(func(tpl *Template) bool {
+		return tpl.UserID == userID
+	}), nil
+}
+
+// ListActive get not paused templates.
+func (m *Memory) ListActive(_ context.Context) ([]*Template, error) {
+	return m.filter(func(tpl *Template) bool {
+		return !tpl.Paused
+	}), nil
+}
+
+func (m *Memory) filter(match func(tpl *Template) bool) []*Template {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Template
+
+	for _, tpl := range m.templates {
+		tpl := tpl
+
+		if match(&tpl) {
+			list = append(list, &tpl)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+
+	return list
+}
@@ -0,0 +1,237 @@
+package templates
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	RuleDaily    = "daily"
+	RuleWeekdays = "weekdays"
+	RuleDays     = "days"
+	RuleInterval = "interval"
+)
+
+// Repository type represent goal templates storage.
+type Repository interface {
+	// Create create new template.
+	Create(ctx context.Context, tpl *Template) error
+
+	// Get get template by ID.
+	Get(ctx context.Context, id int64) (*Template, error)
+
+	// Update update template.
+	Update(ctx context.Context, tpl *Template) error
+
+	// Delete delete template by ID.
+	Delete(ctx context.Context, id int64) error
+
+	// DeleteByUser delete user templates.
+	DeleteByUser(ctx context.Context, userID int64) error
+
+	// ListByUser get user templates.
+	ListByUser(ctx context.Context, userID int64) ([]*Template, error)
+
+	// ListActive get not paused templates.
+	ListActive(ctx context.Context) ([]*Template, error)
+}
+
+// Model type represent model.
+type Model struct {
+	db *sql.DB
+}
+
+// ModelConfig type represent model config.
+type ModelConfig struct {
+	Db *sql.DB
+}
+
+// Template type represent recurring goal plan.
+// Weekdays is bit mask of time.Weekday used by RuleDays,
+// Interval is number of days used by RuleInterval.
+// Dates are calendar dates of user, stored as UTC midnight.
+type Template struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	GoalID    int64      `json:"goal_id"`
+	Type      int64      `json:"type"`
+	Rule      string     `json:"rule"`
+	Weekdays  int64      `json:"weekdays"`
+	Interval  int64      `json:"interval"`
+	StartDate time.Time  `json:"start_date"`
+	LastDate  *time.Time `json:"last_date"`
+	Paused    bool       `json:"paused"`
+}
+
+// NewModel create new Model.
+func NewModel(config ModelConfig) (*Model, error) {
+	m := &Model{
+		db: config.Db,
+	}
+
+	return m, nil
+}
+
+// Create create new template.
+func (m *Model) Create(ctx context.Context, tpl *Template) error {
+	err := m.db.QueryRowContext(ctx, `INSERT INTO goal_templates
+									("user_id", "goal_id", "type", "rule",
+									"weekdays", "interval", "start_date",
+									"last_date", "paused")
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+								RETURNING "id"`,
+		tpl.UserID, tpl.GoalID, tpl.Type, tpl.Rule, tpl.Weekdays,
+		tpl.Interval, tpl.StartDate, tpl.LastDate, tpl.Paused).Scan(&tpl.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get get template by ID.
+func (m *Model) Get(ctx context.Context, id int64) (*Template, error) {
+	var tpl Template
+
+	err := m.db.QueryRowContext(ctx, `SELECT
+									"id", "user_id", "goal_id", "type", "rule",
+									"weekdays", "interval", "start_date",
+									"last_date", "paused"
+									FROM goal_templates
+								WHERE "id" = $1`, id).
+		Scan(&tpl.ID, &tpl.UserID, &tpl.GoalID, &tpl.Type, &tpl.Rule,
+			&tpl.Weekdays, &tpl.Interval, &tpl.StartDate, &tpl.LastDate,
+			&tpl.Paused)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tpl, nil
+}
+
+// Update update template.
+func (m *Model) Update(ctx context.Context, tpl *Template) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE goal_templates SET
+									"goal_id" = $2, "type" = $3, "rule" = $4,
+									"weekdays" = $5, "interval" = $6,
+									"start_date" = $7, "last_date" = $8,
+									"paused" = $9
+								WHERE "id" = $1`,
+		tpl.ID, tpl.GoalID, tpl.Type, tpl.Rule, tpl.Weekdays,
+		tpl.Interval, tpl.StartDate, tpl.LastDate, tpl.Paused)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete delete template by ID.
+func (m *Model) Delete(ctx context.Context, id int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM goal_templates
+								WHERE "id" = $1`, id)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByUser delete user templates.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM goal_templates
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListByUser get user templates.
+func (m *Model) ListByUser(ctx context.Context, userID int64) ([]*Template, error) {
+	return m.list(ctx, `WHERE "user_id" = $1`, userID)
+}
+
+// ListActive get not paused templates.
+func (m *Model) ListActive(ctx context.Context) ([]*Template, error) {
+	return m.list(ctx, `WHERE NOT "paused"`)
+}
+
+func (m *Model) list(ctx context.Context, where string, args ...interface{}) ([]*Template, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT
+									"id", "user_id", "goal_id", "type", "rule",
+									"weekdays", "interval", "start_date",
+									"last_date", "paused"
+									FROM goal_templates
+									`+where+`
+									ORDER BY "id"`, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list []*Template
+
+	for rows.Next() {
+		var tpl Template
+
+		err = rows.Scan(&tpl.ID, &tpl.UserID, &tpl.GoalID, &tpl.Type, &tpl.Rule,
+			&tpl.Weekdays, &tpl.Interval, &tpl.StartDate, &tpl.LastDate,
+			&tpl.Paused)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, &tpl)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return list, nil
+}
+
+// Match check template plans goal on calendar date.
+func (t *Template) Match(date time.Time) bool {
+	day := Day(date)
+	start := Day(t.StartDate)
+
+	if day.Before(start) {
+		return false
+	}
+
+	switch t.Rule {
+	case RuleDaily:
+		return true
+	case RuleWeekdays:
+		return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+	case RuleDays:
+		return t.Weekdays&(1<<uint(day.Weekday())) != 0
+	case RuleInterval:
+		if t.Interval <= 0 {
+			return false
+		}
+
+		days := int64(day.Sub(start).Hours() / 24)
+
+		return days%t.Interval == 0
+	}
+
+	return false
+}
+
+// Day get calendar date of t as UTC midnight.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
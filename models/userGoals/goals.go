@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const (
@@ -17,6 +19,53 @@ const (
 	PhaseFinished = "finished"
 )
 
+// Repository type represent user goals storage.
+type Repository interface {
+	// Create create new user goal.
+	Create(ctx context.Context, uGoal *UserGoal) error
+
+	// Get get user goal by ID.
+	Get(ctx context.Context, id int64) (*UserGoal, error)
+
+	// Delete delete user goal by ID.
+	Delete(ctx context.Context, id int64) error
+
+	// UpdateGoal update user goal id.
+	UpdateGoal(ctx context.Context, id int64, goalID int64) error
+
+	// UpdatePhase update user goal phase.
+	UpdatePhase(ctx context.Context, id int64, phase string) error
+
+	// UpdateStatus update user goal status.
+	UpdateStatus(ctx context.Context, id int64, status string) error
+
+	// List get user goals.
+	List(ctx context.Context) ([]*UserGoal, error)
+
+	// ListByDate get user goals by date.
+	ListByDate(ctx context.Context, date time.Time) ([]*UserGoal, error)
+
+	// ListByUser get user goals by user.
+	ListByUser(ctx context.Context, userID int64) ([]*UserGoal, error)
+
+	// ListByUserAndDate get user goals by user and date.
+	ListByUserAndDate(ctx context.Context, userID int64, date *time.Time) ([]*UserGoal, error)
+
+	// ListByParams get user goals by user, date and type.
+	ListByParams(ctx context.Context, userID int64, date *time.Time, gType int64) ([]*UserGoal, error)
+
+	// ListByPhase get user goals by phase.
+	ListByPhase(ctx context.Context, phase string) ([]*UserGoal, error)
+
+	// DeleteByUser delete user goals of user.
+	DeleteByUser(ctx context.Context, userID int64) error
+
+	// Erase delete user goals and templates of user with goals
+	// typed by user in one transaction. Typed goals chosen by
+	// other users are kept.
+	Erase(ctx context.Context, userID int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db *sql.DB
@@ -50,12 +99,13 @@ func NewModel(config ModelConfig) (*Model, error) {
 
 // Create create new user goal.
 func (m *Model) Create(ctx context.Context, uGoal *UserGoal) error {
-	_, err := m.db.ExecContext(ctx, `INSERT INTO user_goals
+	err := m.db.QueryRowContext(ctx, `INSERT INTO user_goals
 									( "user_id", "goal_id", "type",
 									 "phase","status", "from", "to")
-								VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+								VALUES ($1, $2, $3, $4, $5, $6, $7)
+								RETURNING "id"`,
 		uGoal.UserID, uGoal.GoalID, uGoal.Type,
-		uGoal.Phase, uGoal.Status, uGoal.From, uGoal.To)
+		uGoal.Phase, uGoal.Status, uGoal.From, uGoal.To).Scan(&uGoal.ID)
 
 	if err != nil {
 		return err
@@ -342,3 +392,95 @@ func (m *Model) ListByPhase(ctx context.Context, phase string) ([]*UserGoal, err
 
 	return uGoals, nil
 }
+
+// DeleteByUser delete user goals of user.
+func (m *Model) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM user_goals
+								WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Erase delete user goals and templates of user with goals
+// typed by user in one transaction. Typed goals chosen by
+// other users are kept.
+func (m *Model) Erase(ctx context.Context, userID int64) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT s."goal_id"
+								FROM (
+									SELECT "goal_id", "type" FROM user_goals
+									WHERE "user_id" = $1
+									UNION
+									SELECT "goal_id", "type" FROM goal_templates
+									WHERE "user_id" = $1
+								) s
+								JOIN goal_types t ON t."id" = s."type"
+								WHERE NOT t."from_list"`, userID)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	var typed []int64
+
+	for rows.Next() {
+		var goalID int64
+
+		err = rows.Scan(&goalID)
+
+		if err != nil {
+			_ = rows.Close()
+			_ = tx.Rollback()
+			return err
+		}
+
+		typed = append(typed, goalID)
+	}
+
+	err = rows.Close()
+
+	if err == nil {
+		err = rows.Err()
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM goal_templates WHERE "user_id" = $1`,
+		`DELETE FROM user_goals WHERE "user_id" = $1`,
+	} {
+		_, err = tx.ExecContext(ctx, query, userID)
+
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM goals g
+								WHERE g."id" = ANY($1)
+								AND NOT EXISTS (SELECT 1 FROM user_goals
+									WHERE "goal_id" = g."id")
+								AND NOT EXISTS (SELECT 1 FROM goal_templates
+									WHERE "goal_id" = g."id")`, pq.Array(typed))
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,193 @@
+package userGoals
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory user goals storage.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int64
+	uGoals map[int64]UserGoal
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		uGoals: make(map[int64]UserGoal),
+	}
+}
+
+// Create create new user goal.
+func (m *Memory) Create(_ context.Context, uGoal *UserGoal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	uGoal.ID = m.lastID
+	m.uGoals[uGoal.ID] = *uGoal
+
+	return nil
+}
+
+// Get get user goal by ID.
+func (m *Memory) Get(_ context.Context, id int64) (*UserGoal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	uGoal, ok := m.uGoals[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &uGoal, nil
+}
+
+// Delete delete user goal by ID.
+func (m *Memory) Delete(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.uGoals, id)
+
+	return nil
+}
+
+// UpdateGoal update user goal id.
+func (m *Memory) UpdateGoal(_ context.Context, id int64, goalID int64) error {
+	m.update(id, func(uGoal *UserGoal) {
+		uGoal.GoalID = goalID
+	})
+
+	return nil
+}
+
+// UpdatePhase update user goal phase.
+func (m *Memory) UpdatePhase(_ context.Context, id int64, phase string) error {
+	m.update(id, func(uGoal *UserGoal) {
+		uGoal.Phase = phase
+	})
+
+	return nil
+}
+
+// UpdateStatus update user goal status.
+func (m *Memory) UpdateStatus(_ context.Context, id int64, status string) error {
+	m.update(id, func(uGoal *UserGoal) {
+		uGoal.Status = status
+	})
+
+	return nil
+}
+
+// List get user goals.
+func (m *Memory) List(_ context.Context) ([]*UserGoal, error) {
+	return m.filter(func(uGoal *UserGoal) bool {
+		return true
+	}), nil
+}
+
+// ListByDate get user goals by date.
+func (m *Memory) ListByDate(_ context.Context, date time.Time) ([]*UserGoal, error) {
+	return m.filter(func(uGoal *UserGoal) bool {
+		return date.After(uGoal.From) && date.Before(uGoal.To)
+	}), nil
+}
+
+// ListByUser get user goals by user.
+func (m *Memory) ListByUser(_ context.Context, userID int64) ([]*UserGoal, error) {
+	return m.filter(func(uGoal *UserGoal) bool {
+		return uGoal.UserID == userID
+	}), nil
+}
+
+// ListByUserAndDate get user goals by user and date.
+func (m *Memory) ListByUserAndDate(_ context.Context, userID int64, date *time.Time) ([]*UserGoal, error) {
+	return m.filter(func(uGoal *UserGoal) bool {
+		return uGoal.UserID == userID && within(uGoal, *date)
+	}), nil
+}
+
+// ListByParams get user goals by user, date and type.
+func (m *Memory) ListByParams(_ context.Context, userID int64, date *time.Time, gType int64) ([]*UserGoal, error) {
+	return m.filter(func(uGoal *UserGoal) bool {
+		return uGoal.UserID == userID && uGoal.Type == gType && within(uGoal, *date)
+	}), nil
+}
+
+// ListByPhase get user goals by phase.
+func (m *Memory) ListByPhase(_ context.Context, phase string) ([]*UserGoal, error) {
+	return m.filter(func(uGoal *UserGoal) bool {
+		return uGoal.Phase == phase
+	}), nil
+}
+
+func (m *Memory) update(id int64, f func(uGoal *UserGoal)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uGoal, ok := m.uGoals[id]
+
+	if !ok {
+		return
+	}
+
+	f(&uGoal)
+	m.uGoals[id] = uGoal
+}
+
+func (m *Memory) filter(f func(uGoal *UserGoal) bool) []*UserGoal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var uGoals []*UserGoal
+
+	for _, uGoal := range m.uGoals {
+		uGoal := uGoal
+
+		if f(&uGoal) {
+			uGoals = append(uGoals, &uGoal)
+		}
+	}
+
+	sort.Slice(uGoals, func(i, j int) bool {
+		return uGoals[i].ID < uGoals[j].ID
+	})
+
+	return uGoals
+}
+
+// within check date is inside user goal bounds inclusively.
+func within(uGoal *UserGoal, date time.Time) bool {
+	return !date.Before(uGoal.From) && !date.After(uGoal.To)
+}
+
+// DeleteByUser delete user goals of user.
+func (m *Memory) DeleteByUser(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, uGoal := range m.uGoals {
+		if uGoal.UserID == userID {
+			delete(m.uGoals, id)
+		}
+	}
+
+	return nil
+}
+
+// Erase delete user goals of user, in-memory storage
+// keeps no templates and goals.
+func (m *Memory) Erase(ctx context.Context, userID int64) error {
+	return m.DeleteByUser(ctx, userID)
+}
@@ -0,0 +1,156 @@
+package users
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory users storage.
+type Memory struct {
+	mu    sync.RWMutex
+	users map[int64]User
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		users: make(map[int64]User),
+	}
+}
+
+// Create create new user.
+func (m *Memory) Create(_ context.Context, user *User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[user.ID]; ok {
+		return ErrExists
+	}
+
+	createdAt := time.Now()
+	stored := *user
+	stored.Active = true
+	stored.CreatedAt = &createdAt
+	m.users[user.ID] = stored
+
+	return nil
+}
+
+// List get user list.
+func (m *Memory) List(_ context.Context) ([]*User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var users []*User
+
+	for _, user := range m.users {
+		user := user
+		users = append(users, &user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users, nil
+}
+
+// Get get user by ID.
+func (m *Memory) Get(_ context.Context, id int64) (*User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user, ok := m.users[id]
+
+	if !ok {
+		return nil, nil
+	}
+
+	return &user, nil
+}
+
+// UpdateState update user state.
+func (m *Memory) UpdateState(_ context.Context, userID int64, state string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[userID]
+
+	if !ok {
+		return nil
+	}
+
+	user.State = state
+	m.users[userID] = user
+
+	return nil
+}
+
+// UpdateLocation update user city and timezone.
+func (m *Memory) UpdateLocation(_ context.Context, userID int64, city string, timezone string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[userID]
+
+	if !ok {
+		return nil
+	}
+
+	user.City = city
+	user.Timezone = timezone
+	m.users[userID] = user
+
+	return nil
+}
+
+// UpdateReminders update user reminder hours.
+func (m *Memory) UpdateReminders(_ context.Context, userID int64, reminders Reminders) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[userID]
+
+	if !ok {
+		return nil
+	}
+
+	user.Reminders = reminders
+	m.users[userID] = user
+
+	return nil
+}
+
+// UpdateDayStart update hour when user day starts.
+func (m *Memory) UpdateDayStart(_ context.Context, userID int64, hour int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[userID]
+
+	if !ok {
+		return nil
+	}
+
+	user.DayStart = hour
+	m.users[userID] = user
+
+	return nil
+}
+
+// Delete delete user by ID.
+func (m *Memory) Delete(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.users, id)
+
+	return nil
+}
@@ -13,11 +13,46 @@ import (
 	"github.com/lib/pq"
 )
 
+const (
+	// DefaultTimezone timezone of users without known city.
+	DefaultTimezone = "Asia/Yekaterinburg"
+
+	// DefaultDayStart hour when user day starts by default.
+	DefaultDayStart = 6
+)
+
 var (
 	// ErrExists user exists.
 	ErrExists = errors.New("user exists")
 )
 
+// Repository type represent users storage.
+type Repository interface {
+	// Create create new user.
+	Create(ctx context.Context, user *User) error
+
+	// List get user list.
+	List(ctx context.Context) ([]*User, error)
+
+	// Get get user by ID.
+	Get(ctx context.Context, id int64) (*User, error)
+
+	// UpdateState update user state.
+	UpdateState(ctx context.Context, userID int64, state string) error
+
+	// UpdateLocation update user city and timezone.
+	UpdateLocation(ctx context.Context, userID int64, city string, timezone string) error
+
+	// UpdateReminders update user reminder hours.
+	UpdateReminders(ctx context.Context, userID int64, reminders Reminders) error
+
+	// UpdateDayStart update hour when user day starts.
+	UpdateDayStart(ctx context.Context, userID int64, hour int) error
+
+	// Delete delete user by ID.
+	Delete(ctx context.Context, id int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db    *sql.DB
@@ -39,9 +74,19 @@ type User struct {
 	Timezone  string     `json:"timezone"`
 	Active    bool       `json:"status"`
 	State     string     `json:"state"`
+	Reminders Reminders  `json:"reminders"`
+	DayStart  int        `json:"day_start"`
 	CreatedAt *time.Time `json:"created_at"`
 }
 
+// Reminders preferred reminder hours in user location,
+// nil hour means hour chosen by notificator.
+type Reminders struct {
+	Morning  *int `json:"morning"`
+	Evening  *int `json:"evening"`
+	Planning *int `json:"planning"`
+}
+
 // NewModel create new Model.
 func NewModel(config ModelConfig) (*Model, error) {
 	m := &Model{
@@ -56,10 +101,10 @@ func NewModel(config ModelConfig) (*Model, error) {
 func (m *Model) Create(ctx context.Context, user *User) error {
 	_, err := m.db.ExecContext(ctx, `INSERT INTO users
 									( "id", "first_name","last_name", 
-									 "timezone", "city", "state")
-								VALUES ($1, $2, $3, $4, $5, $6)`,
+									 "timezone", "city", "state", "day_start")
+								VALUES ($1, $2, $3, $4, $5, $6, $7)`,
 		user.ID, user.FirstName, user.LastName,
-		user.Timezone, user.City, user.State)
+		user.Timezone, user.City, user.State, user.DayStart)
 
 	if err != nil {
 		if pgErr, ok := err.(*pq.Error); ok {
@@ -79,7 +124,8 @@ func (m *Model) List(ctx context.Context) ([]*User, error) {
 	rows, err := m.db.QueryContext(ctx, `SELECT  
 									"id", "first_name","last_name", 
        								"active", "timezone", "created_at",
-									"state", "city"
+									"state", "city", "morning_hour",
+									"evening_hour", "planning_hour", "day_start"
 									FROM users
 									ORDER BY "id"`)
 
@@ -93,7 +139,9 @@ func (m *Model) List(ctx context.Context) ([]*User, error) {
 		var user User
 
 		err = rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Active,
-			&user.Timezone, &user.CreatedAt, &user.State, &user.City)
+			&user.Timezone, &user.CreatedAt, &user.State, &user.City,
+			&user.Reminders.Morning, &user.Reminders.Evening, &user.Reminders.Planning,
+			&user.DayStart)
 
 		if err != nil {
 			return nil, err
@@ -126,12 +174,15 @@ func (m *Model) Get(ctx context.Context, id int64) (*User, error) {
 	err = m.db.QueryRowContext(ctx, `SELECT  
 									"id", "first_name","last_name", 
        								"active", "timezone", "created_at",
-									"state", "city"
+									"state", "city", "morning_hour",
+									"evening_hour", "planning_hour", "day_start"
 									     FROM users
 								WHERE id = $1`,
 		id,
 	).Scan(&user.ID, &user.FirstName, &user.LastName, &user.Active,
-		&user.Timezone, &user.CreatedAt, &user.State, &user.City)
+		&user.Timezone, &user.CreatedAt, &user.State, &user.City,
+		&user.Reminders.Morning, &user.Reminders.Evening, &user.Reminders.Planning,
+		&user.DayStart)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -151,7 +202,10 @@ func (m *Model) Get(ctx context.Context, id int64) (*User, error) {
 }
 
 func (m *Model) getCache(ctx context.Context, id int64) (*User, error) {
-	var user User
+	// users cached before day start setting have no such field.
+	user := User{
+		DayStart: DefaultDayStart,
+	}
 
 	userRaw, err := m.cache.Get(ctx, m.key(id)).Result()
 
@@ -203,6 +257,85 @@ func (m *Model) UpdateState(ctx context.Context, userID int64, state string) err
 	return nil
 }
 
+// UpdateLocation update user city and timezone.
+func (m *Model) UpdateLocation(ctx context.Context, userID int64, city string, timezone string) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE users SET
+									"city" = $2, "timezone" = $3
+								WHERE id = $1`,
+		userID, city, timezone)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.cache.Del(ctx, m.key(userID)).Err()
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+// UpdateReminders update user reminder hours.
+func (m *Model) UpdateReminders(ctx context.Context, userID int64, reminders Reminders) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE users SET
+									"morning_hour" = $2, "evening_hour" = $3,
+									"planning_hour" = $4
+								WHERE id = $1`,
+		userID, reminders.Morning, reminders.Evening, reminders.Planning)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.cache.Del(ctx, m.key(userID)).Err()
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+// UpdateDayStart update hour when user day starts.
+func (m *Model) UpdateDayStart(ctx context.Context, userID int64, hour int) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE users SET
+									"day_start" = $2
+								WHERE id = $1`,
+		userID, hour)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.cache.Del(ctx, m.key(userID)).Err()
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+// Delete delete user by ID.
+func (m *Model) Delete(ctx context.Context, id int64) error {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM users
+								WHERE id = $1`, id)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.cache.Del(ctx, m.key(id)).Err()
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
 func (m *Model) key(id int64) string {
 	return fmt.Sprintf("user_%d", id)
 }
@@ -233,8 +366,9 @@ func (u *User) Date(t time.Time) (*time.Time, error) {
 	return &date, nil
 }
 
-// StartDate get start date in user location.
-func (u *User) StartDate(t time.Time) (*time.Time, error) {
+// Day get date of user day containing t in user location,
+// day before day start hour belongs to previous date.
+func (u *User) Day(t time.Time) (*time.Time, error) {
 	location, err := time.LoadLocation(u.Timezone)
 
 	if err != nil {
@@ -242,21 +376,59 @@ func (u *User) StartDate(t time.Time) (*time.Time, error) {
 	}
 
 	year, month, day := t.In(location).Date()
-	start := time.Date(year, month, day,
-		6, 0, 0, 0, t.Location())
+	start := time.Date(year, month, day, u.DayStart, 0, 0, 0, location)
+
+	if t.Before(start) {
+		day--
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, location)
+
+	return &date, nil
+}
+
+// Midday get middle of user day which starts on calendar date, it stays
+// inside goal period of that date after day start is changed. For day
+// starts from noon it falls on the next calendar date.
+func (u *User) Midday(date time.Time) (*time.Time, error) {
+	location, err := time.LoadLocation(u.Timezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hour := u.DayStart + 12
+	midday := time.Date(date.Year(), date.Month(), date.Day()+hour/24,
+		hour%24, 0, 0, 0, location)
+
+	return &midday, nil
+}
+
+// StartDate get start of user day containing t.
+func (u *User) StartDate(t time.Time) (*time.Time, error) {
+	day, err := u.Day(t)
+
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(),
+		u.DayStart, 0, 0, 0, day.Location())
 
 	return &start, nil
 }
 
-// EndDate get end date in user location.
+// EndDate get end of user day containing t. Day is counted
+// by calendar, so it is 23 or 25 hours long on DST transitions.
 func (u *User) EndDate(t time.Time) (*time.Time, error) {
-	start, err := u.StartDate(t)
+	day, err := u.Day(t)
 
 	if err != nil {
 		return nil, err
 	}
 
-	end := start.Add(24*time.Hour - time.Second)
+	end := time.Date(day.Year(), day.Month(), day.Day()+1,
+		u.DayStart, 0, 0, 0, day.Location()).Add(-time.Second)
 
 	return &end, nil
 }
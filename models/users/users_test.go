@@ -0,0 +1,242 @@
+package users
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+
+	if err != nil {
+		t.Skipf("timezone %s: %v", name, err)
+	}
+
+	return location
+}
+
+func TestDayBoundaries(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	yekaterinburg := mustLocation(t, "Asia/Yekaterinburg")
+
+	tests := []struct {
+		name     string
+		timezone string
+		dayStart int
+		t        time.Time
+		day      time.Time
+		start    time.Time
+		end      time.Time
+		length   time.Duration
+	}{
+		{
+			name:     "default day start",
+			timezone: "Asia/Yekaterinburg",
+			dayStart: DefaultDayStart,
+			t:        time.Date(2026, time.May, 10, 12, 0, 0, 0, yekaterinburg),
+			day:      time.Date(2026, time.May, 10, 0, 0, 0, 0, yekaterinburg),
+			start:    time.Date(2026, time.May, 10, 6, 0, 0, 0, yekaterinburg),
+			end:      time.Date(2026, time.May, 11, 5, 59, 59, 0, yekaterinburg),
+			length:   24 * time.Hour,
+		},
+		{
+			name:     "before day start belongs to previous date",
+			timezone: "Asia/Yekaterinburg",
+			dayStart: DefaultDayStart,
+			t:        time.Date(2026, time.May, 10, 5, 59, 0, 0, yekaterinburg),
+			day:      time.Date(2026, time.May, 9, 0, 0, 0, 0, yekaterinburg),
+			start:    time.Date(2026, time.May, 9, 6, 0, 0, 0, yekaterinburg),
+			end:      time.Date(2026, time.May, 10, 5, 59, 59, 0, yekaterinburg),
+			length:   24 * time.Hour,
+		},
+		{
+			name:     "late day start",
+			timezone: "Asia/Yekaterinburg",
+			dayStart: 18,
+			t:        time.Date(2026, time.May, 10, 3, 0, 0, 0, yekaterinburg),
+			day:      time.Date(2026, time.May, 9, 0, 0, 0, 0, yekaterinburg),
+			start:    time.Date(2026, time.May, 9, 18, 0, 0, 0, yekaterinburg),
+			end:      time.Date(2026, time.May, 10, 17, 59, 59, 0, yekaterinburg),
+			length:   24 * time.Hour,
+		},
+		{
+			name:     "midnight day start",
+			timezone: "Asia/Yekaterinburg",
+			dayStart: 0,
+			t:        time.Date(2026, time.May, 10, 0, 0, 0, 0, yekaterinburg),
+			day:      time.Date(2026, time.May, 10, 0, 0, 0, 0, yekaterinburg),
+			start:    time.Date(2026, time.May, 10, 0, 0, 0, 0, yekaterinburg),
+			end:      time.Date(2026, time.May, 10, 23, 59, 59, 0, yekaterinburg),
+			length:   24 * time.Hour,
+		},
+		{
+			name:     "dst forward",
+			timezone: "Europe/Berlin",
+			dayStart: DefaultDayStart,
+			t:        time.Date(2026, time.March, 28, 23, 0, 0, 0, berlin),
+			day:      time.Date(2026, time.March, 28, 0, 0, 0, 0, berlin),
+			start:    time.Date(2026, time.March, 28, 6, 0, 0, 0, berlin),
+			end:      time.Date(2026, time.March, 29, 5, 59, 59, 0, berlin),
+			length:   23 * time.Hour,
+		},
+		{
+			name:     "dst forward after gap",
+			timezone: "Europe/Berlin",
+			dayStart: DefaultDayStart,
+			t:        time.Date(2026, time.March, 29, 3, 30, 0, 0, berlin),
+			day:      time.Date(2026, time.March, 28, 0, 0, 0, 0, berlin),
+			start:    time.Date(2026, time.March, 28, 6, 0, 0, 0, berlin),
+			end:      time.Date(2026, time.March, 29, 5, 59, 59, 0, berlin),
+			length:   23 * time.Hour,
+		},
+		{
+			name:     "dst forward midnight day start",
+			timezone: "Europe/Berlin",
+			dayStart: 0,
+			t:        time.Date(2026, time.March, 29, 12, 0, 0, 0, berlin),
+			day:      time.Date(2026, time.March, 29, 0, 0, 0, 0, berlin),
+			start:    time.Date(2026, time.March, 29, 0, 0, 0, 0, berlin),
+			end:      time.Date(2026, time.March, 29, 23, 59, 59, 0, berlin),
+			length:   23 * time.Hour,
+		},
+		{
+			name:     "dst back",
+			timezone: "Europe/Berlin",
+			dayStart: DefaultDayStart,
+			t:        time.Date(2026, time.October, 24, 12, 0, 0, 0, berlin),
+			day:      time.Date(2026, time.October, 24, 0, 0, 0, 0, berlin),
+			start:    time.Date(2026, time.October, 24, 6, 0, 0, 0, berlin),
+			end:      time.Date(2026, time.October, 25, 5, 59, 59, 0, berlin),
+			length:   25 * time.Hour,
+		},
+		{
+			name:     "dst back repeated hour",
+			timezone: "Europe/Berlin",
+			dayStart: DefaultDayStart,
+			// Second 02:30 of the day, after clocks are turned back.
+			t:      time.Date(2026, time.October, 25, 1, 30, 0, 0, time.UTC),
+			day:    time.Date(2026, time.October, 24, 0, 0, 0, 0, berlin),
+			start:  time.Date(2026, time.October, 24, 6, 0, 0, 0, berlin),
+			end:    time.Date(2026, time.October, 25, 5, 59, 59, 0, berlin),
+			length: 25 * time.Hour,
+		},
+		{
+			name:     "dst back late day start",
+			timezone: "Europe/Berlin",
+			dayStart: 20,
+			t:        time.Date(2026, time.October, 25, 12, 0, 0, 0, berlin),
+			day:      time.Date(2026, time.October, 24, 0, 0, 0, 0, berlin),
+			start:    time.Date(2026, time.October, 24, 20, 0, 0, 0, berlin),
+			end:      time.Date(2026, time.October, 25, 19, 59, 59, 0, berlin),
+			length:   25 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &User{Timezone: tt.timezone, DayStart: tt.dayStart}
+
+			day, err := user.Day(tt.t)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !day.Equal(tt.day) {
+				t.Errorf("Day = %s, want %s", day, tt.day)
+			}
+
+			start, err := user.StartDate(tt.t)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !start.Equal(tt.start) {
+				t.Errorf("StartDate = %s, want %s", start, tt.start)
+			}
+
+			end, err := user.EndDate(tt.t)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !end.Equal(tt.end) {
+				t.Errorf("EndDate = %s, want %s", end, tt.end)
+			}
+
+			if length := end.Sub(*start) + time.Second; length != tt.length {
+				t.Errorf("day length = %s, want %s", length, tt.length)
+			}
+		})
+	}
+}
+
+func TestMidday(t *testing.T) {
+	yekaterinburg := mustLocation(t, "Asia/Yekaterinburg")
+
+	tests := []struct {
+		dayStart int
+		want     time.Time
+	}{
+		{dayStart: 0, want: time.Date(2026, time.May, 9, 12, 0, 0, 0, yekaterinburg)},
+		{dayStart: DefaultDayStart, want: time.Date(2026, time.May, 9, 18, 0, 0, 0, yekaterinburg)},
+		{dayStart: 11, want: time.Date(2026, time.May, 9, 23, 0, 0, 0, yekaterinburg)},
+		{dayStart: 12, want: time.Date(2026, time.May, 10, 0, 0, 0, 0, yekaterinburg)},
+		{dayStart: 18, want: time.Date(2026, time.May, 10, 6, 0, 0, 0, yekaterinburg)},
+		{dayStart: 23, want: time.Date(2026, time.May, 10, 11, 0, 0, 0, yekaterinburg)},
+	}
+
+	date := time.Date(2026, time.May, 9, 0, 0, 0, 0, yekaterinburg)
+
+	for _, tt := range tests {
+		user := &User{Timezone: "Asia/Yekaterinburg", DayStart: tt.dayStart}
+
+		midday, err := user.Midday(date)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !midday.Equal(tt.want) {
+			t.Errorf("day start %d: Midday = %s, want %s", tt.dayStart, midday, tt.want)
+		}
+	}
+}
+
+// Midday must belong to user day of its date for any day start,
+// including DST transition days.
+func TestMiddayInsideDay(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+
+	dates := []time.Time{
+		time.Date(2026, time.March, 28, 0, 0, 0, 0, berlin),
+		time.Date(2026, time.March, 29, 0, 0, 0, 0, berlin),
+		time.Date(2026, time.October, 24, 0, 0, 0, 0, berlin),
+		time.Date(2026, time.October, 25, 0, 0, 0, 0, berlin),
+	}
+
+	for dayStart := 0; dayStart < 24; dayStart++ {
+		user := &User{Timezone: "Europe/Berlin", DayStart: dayStart}
+
+		for _, date := range dates {
+			midday, err := user.Midday(date)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			day, err := user.Day(*midday)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !day.Equal(date) {
+				t.Errorf("day start %d: Midday(%s) = %s is in day %s",
+					dayStart, date.Format("2006-01-02"), midday, day.Format("2006-01-02"))
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package whiteList
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+var (
+	_ Repository = (*Model)(nil)
+	_ Repository = (*Memory)(nil)
+)
+
+// Memory type represent in-memory white list storage.
+type Memory struct {
+	mu    sync.RWMutex
+	users map[int64]struct{}
+}
+
+// NewMemory create new Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		users: make(map[int64]struct{}),
+	}
+}
+
+// Create create new item.
+func (m *Memory) Create(_ context.Context, item *Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[item.UserID]; ok {
+		return ErrExists
+	}
+
+	m.users[item.UserID] = struct{}{}
+
+	return nil
+}
+
+// Check check user in white list.
+func (m *Memory) Check(_ context.Context, userID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.users[userID]
+
+	return ok, nil
+}
+
+// List get white list page.
+func (m *Memory) List(_ context.Context, limit, offset int) ([]*Item, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]int64, 0, len(m.users))
+
+	for userID := range m.users {
+		ids = append(ids, userID)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	items := make([]*Item, 0, limit)
+
+	for i := offset; i < len(ids) && len(items) < limit; i++ {
+		items = append(items, &Item{UserID: ids[i]})
+	}
+
+	return items, nil
+}
+
+// Count count white list items.
+func (m *Memory) Count(_ context.Context) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.users)), nil
+}
+
+// Delete delete user from white list.
+func (m *Memory) Delete(_ context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[userID]; !ok {
+		return ErrNotFound
+	}
+
+	delete(m.users, userID)
+
+	return nil
+}
@@ -3,8 +3,37 @@ package whiteList
 import (
 	"context"
 	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound user not in white list.
+	ErrNotFound = errors.New("white list item not found")
+
+	// ErrExists user already in white list.
+	ErrExists = errors.New("white list item exists")
 )
 
+// Repository type represent white list storage.
+type Repository interface {
+	// Create create new item.
+	Create(ctx context.Context, item *Item) error
+
+	// Check check user in white list.
+	Check(ctx context.Context, userID int64) (bool, error)
+
+	// List get white list page.
+	List(ctx context.Context, limit, offset int) ([]*Item, error)
+
+	// Count count white list items.
+	Count(ctx context.Context) (int64, error)
+
+	// Delete delete user from white list.
+	Delete(ctx context.Context, userID int64) error
+}
+
 // Model type represent model.
 type Model struct {
 	db *sql.DB
@@ -37,6 +66,12 @@ func (m *Model) Create(ctx context.Context, item *Item) error {
 		item.UserID)
 
 	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" {
+				return ErrExists
+			}
+		}
+
 		return err
 	}
 
@@ -60,3 +95,73 @@ func (m *Model) Check(ctx context.Context, userID int64) (bool, error) {
 
 	return true, nil
 }
+
+// List get white list page.
+func (m *Model) List(ctx context.Context, limit, offset int) ([]*Item, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT "user_id"
+		FROM white_list
+		ORDER BY "user_id"
+		LIMIT $1 OFFSET $2`, limit, offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := make([]*Item, 0, limit)
+
+	for rows.Next() {
+		var item Item
+
+		err = rows.Scan(&item.UserID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, &item)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return items, nil
+}
+
+// Count count white list items.
+func (m *Model) Count(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := m.db.QueryRowContext(ctx, `SELECT count(*) FROM white_list`).
+		Scan(&count)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Delete delete user from white list.
+func (m *Model) Delete(ctx context.Context, userID int64) error {
+	res, err := m.db.ExecContext(ctx, `DELETE FROM white_list
+		WHERE "user_id" = $1`, userID)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -2,189 +2,212 @@ package notificator
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/Zetkolink/oracle/models/goalTypes"
-	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/outbox"
+	"github.com/Zetkolink/oracle/models/reminders"
 	"github.com/Zetkolink/oracle/models/userGoals"
 	"github.com/Zetkolink/oracle/models/users"
-	"github.com/go-redis/redis/v8"
+)
+
+const (
+	dispatchBatch = 50
+
+	// maxAttempts delivery attempts before message is failed.
+	maxAttempts = 8
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+var (
+	errNoUser      = errors.New("notification user not found")
+	errNoDeliverer = errors.New("notification user transport not served")
 )
 
 type Notificator struct {
-	Messages    chan *Message
-	models      ModelsSet
-	redisClient *redis.Client
+	deliverers []Deliverer
+	models     ModelsSet
 }
 
 type Config struct {
-	Models      ModelsSet
-	RedisClient *redis.Client
+	// Deliverers transports deliverers, user notification is
+	// delivered by deliverer which owns user.
+	Deliverers []Deliverer
+	Models     ModelsSet
 }
 
 type ModelsSet struct {
-	Users     *users.Model
-	UserGoals *userGoals.Model
-	GoalTypes *goalTypes.Model
+	Users     users.Repository
+	UserGoals userGoals.Repository
+	GoalTypes goalTypes.Repository
+	Outbox    outbox.Repository
+	Reminders reminders.Repository
+}
+
+// Deliverer service which renders and sends notifications to users.
+type Deliverer interface {
+	// Owns check user is served by deliverer.
+	Owns(userID int64) bool
+
+	Deliver(ctx context.Context, message *Message) error
 }
 
 type Message struct {
-	User *users.User
-	Code string
-	Text string
+	User   *users.User
+	Code   string
+	Text   string
+	Params map[string]interface{}
 }
 
 func NewNotificator(config Config) *Notificator {
 	return &Notificator{
-		models:      config.Models,
-		Messages:    make(chan *Message),
-		redisClient: config.RedisClient,
+		deliverers: config.Deliverers,
+		models:     config.Models,
 	}
 }
 
-func (n *Notificator) Run() {
-	go func() {
-		for {
-			ctx := context.Background()
-			usrs, err := n.models.Users.List(ctx)
-
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-
-			for _, user := range usrs {
-				uDate, err := user.Date(time.Now())
-
-				if err != nil {
-					log.Println(err)
-					continue
-				}
-
-				nextDay := uDate.AddDate(0, 0, 1)
-				nextDayGoals, err := n.models.UserGoals.ListByUserAndDate(ctx,
-					user.ID, &nextDay)
-
-				if err != nil {
-					log.Println(err)
-					continue
-				}
-
-				uGoals, err := n.models.UserGoals.ListByUserAndDate(ctx, user.ID, uDate)
-
-				if err != nil {
-					log.Println(err)
-					continue
-				}
-
-				gTypes, err := n.models.GoalTypes.List(ctx)
-
-				if err != nil {
-					log.Println(err)
-					continue
-				}
-
-				from := 12
-				to := 14
-
-				for _, uGoal := range uGoals {
-					if uGoal.Type == goalTypes.Awaking {
-						switch uGoal.GoalID {
-						case int64(goals.Awaking6):
-							from = 6
-							to = 8
-						case int64(goals.Awaking8):
-							from = 8
-							to = 10
-						case int64(goals.Awaking10):
-							from = 10
-							to = 12
-						}
-					}
-				}
-
-				if uDate.Hour() >= from+10 && uDate.Hour() <= to+10 &&
-					len(nextDayGoals) < len(gTypes) {
-
-					err = n.Send(user, "next_day", "")
-
-					if err != nil {
-						log.Println(err)
-					}
-				}
-
-				if len(uGoals) == 0 {
-					continue
-				}
-
-				if uDate.Hour() >= from && uDate.Hour() <= to {
-					err = n.Send(user, "task_list", "")
-
-					if err != nil {
-						log.Println(err)
-					}
-				}
-
-				if uDate.Hour() >= from+8 && uDate.Hour() <= to+8 {
-					err = n.Send(user, "mark_tasks", "")
-
-					if err != nil {
-						log.Println(err)
-					}
-				}
-			}
-
-			time.Sleep(1 * time.Hour)
-		}
-	}()
+func (n *Notificator) Send(user *users.User, code string, text string) error {
+	return n.SendParams(user, code, text, nil)
 }
 
-func (n *Notificator) Send(user *users.User, code string, text string) error {
-	ok, err := n.sendCheck(user, code, text)
+// SendParams put notification to outbox, params are used by
+// services to render it, e.g. keyboard payloads. Same
+// notification is sent to user once a day.
+func (n *Notificator) SendParams(user *users.User, code string, text string,
+	params map[string]interface{}) error {
+
+	key, err := n.key(user, code, text, params)
 
 	if err != nil {
 		return err
 	}
 
-	if ok {
-		return nil
+	err = n.models.Outbox.Create(context.Background(), &outbox.Message{
+		Key:         key,
+		UserID:      user.ID,
+		Code:        code,
+		Text:        text,
+		Params:      params,
+		NextAttempt: time.Now(),
+	})
+
+	if err != nil && err != outbox.ErrExists {
+		return err
 	}
 
-	err = n.sendMark(user, code, text)
+	return nil
+}
+
+// key get notification idempotency key.
+func (n *Notificator) key(user *users.User, code string, text string,
+	params map[string]interface{}) (string, error) {
+
+	day, err := user.Day(time.Now())
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	n.Messages <- &Message{
-		User: user,
-		Code: code,
-		Text: text,
+	paramsRaw, err := json.Marshal(params)
+
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	hash := sha1.Sum(append([]byte(text), paramsRaw...))
+
+	return fmt.Sprintf("%d_%s_%s_%x", user.ID, code,
+		day.Format("2006-01-02"), hash[:8]), nil
 }
 
-func (n *Notificator) sendCheck(user *users.User, code string, text string) (bool, error) {
-	err := n.redisClient.Get(context.Background(), fmt.Sprintf("%d_%s_%s",
-		user.ID, code, text)).Err()
+// Dispatch deliver due outbox messages.
+func (n *Notificator) Dispatch(ctx context.Context) error {
+	msgs, err := n.models.Outbox.ListDue(ctx, time.Now(), dispatchBatch)
 
 	if err != nil {
-		if err == redis.Nil {
-			return false, nil
+		return err
+	}
+
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = n.deliver(ctx, msg)
+
+		if err != nil {
+			log.Println(err)
 		}
+	}
 
-		return false, err
+	return nil
+}
+
+// deliverer get deliverer which owns user.
+func (n *Notificator) deliverer(userID int64) Deliverer {
+	for _, d := range n.deliverers {
+		if d.Owns(userID) {
+			return d
+		}
 	}
 
-	return true, nil
+	return nil
 }
 
-func (n *Notificator) sendMark(user *users.User, code string, text string) error {
-	err := n.redisClient.Set(context.Background(), fmt.Sprintf("%d_%s_%s",
-		user.ID, code, text), true, 8*time.Hour).Err()
+// deliver send outbox message and save attempt result,
+// failed message is retried with exponential backoff.
+func (n *Notificator) deliver(ctx context.Context, msg *outbox.Message) error {
+	user, err := n.models.Users.Get(ctx, msg.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	sendErr := errNoUser
+	deliverer := n.deliverer(msg.UserID)
+
+	if user != nil && deliverer == nil {
+		sendErr = errNoDeliverer
+	}
+
+	if user != nil && deliverer != nil {
+		sendErr = deliverer.Deliver(ctx, &Message{
+			User:   user,
+			Code:   msg.Code,
+			Text:   msg.Text,
+			Params: msg.Params,
+		})
+	}
+
+	now := time.Now()
+	msg.Attempts++
+	attempt := &outbox.Attempt{
+		MessageID: msg.ID,
+		Number:    msg.Attempts,
+	}
+
+	switch {
+	case sendErr == nil:
+		msg.Status = outbox.StatusSent
+		msg.SentAt = &now
+		msg.LastError = ""
+	case user == nil || deliverer == nil || msg.Attempts >= maxAttempts:
+		msg.Status = outbox.StatusFailed
+		msg.LastError = sendErr.Error()
+		attempt.Error = sendErr.Error()
+	default:
+		msg.NextAttempt = now.Add(backoff(msg.Attempts))
+		msg.LastError = sendErr.Error()
+		attempt.Error = sendErr.Error()
+	}
+
+	err = n.models.Outbox.SaveAttempt(ctx, msg, attempt)
 
 	if err != nil {
 		return err
@@ -192,3 +215,15 @@ func (n *Notificator) sendMark(user *users.User, code string, text string) error
 
 	return nil
 }
+
+// backoff get delay after failed attempt, it doubles
+// with every attempt up to maxBackoff.
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff << uint(attempts-1)
+
+	if delay <= 0 || delay > maxBackoff {
+		return maxBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,398 @@
+package notificator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/reminders"
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+// Reminder codes.
+const (
+	codeTaskList     = "task_list"
+	codeMarkTasks    = "mark_tasks"
+	codeNextDay      = "next_day"
+	codeWeeklyReport = "weekly_report"
+)
+
+// Reminder times in minutes after midnight of user day date.
+const (
+	defaultWake    = 12 * 60
+	eveningOffset  = 8 * 60
+	planningOffset = 10 * 60
+	weeklyReport   = 20 * 60
+)
+
+const (
+	remindBatch = 100
+
+	// dateLayout layout of task list date param.
+	dateLayout = "2006-01-02"
+
+	// maxDelay reminder is skipped when it is late more,
+	// e.g. after downtime.
+	maxDelay = time.Hour
+
+	// planDays days searched for next reminder.
+	planDays = 8
+)
+
+var (
+	reminderCodes = []string{
+		codeTaskList,
+		codeMarkTasks,
+		codeNextDay,
+		codeWeeklyReport,
+	}
+
+	// wakeTimes wake up time of awaking goals.
+	wakeTimes = map[int64]int{
+		int64(goals.Awaking6):  6 * 60,
+		int64(goals.Awaking8):  8 * 60,
+		int64(goals.Awaking10): 10 * 60,
+	}
+)
+
+// Plan plan reminders of all users.
+func (n *Notificator) Plan(ctx context.Context) error {
+	usrs, err := n.models.Users.List(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, user := range usrs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = n.PlanUser(ctx, user)
+
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	return nil
+}
+
+// PlanUser plan next user reminders, should be called when
+// reminder settings or goals are changed. Reminders which are
+// already due are left to Notify.
+func (n *Notificator) PlanUser(ctx context.Context, user *users.User) error {
+	now := time.Now()
+
+	for _, code := range reminderCodes {
+		current, err := n.models.Reminders.Get(ctx, user.ID, code)
+
+		if err != nil {
+			return err
+		}
+
+		if current != nil && !current.Due.After(now) {
+			continue
+		}
+
+		due, err := n.next(ctx, user, code, now)
+
+		if err != nil {
+			return err
+		}
+
+		if due == nil {
+			continue
+		}
+
+		err = n.models.Reminders.Set(ctx, &reminders.Reminder{
+			UserID: user.ID,
+			Code:   code,
+			Due:    *due,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Notify send due reminders and plan next ones. Reminder is
+// claimed by removing it, so it is sent by one instance.
+func (n *Notificator) Notify(ctx context.Context) error {
+	now := time.Now()
+	list, err := n.models.Reminders.ListDue(ctx, now, remindBatch)
+
+	if err != nil {
+		return err
+	}
+
+	for _, reminder := range list {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		claimed, err := n.models.Reminders.Remove(ctx, reminder.UserID,
+			reminder.Code)
+
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		err = n.remind(ctx, reminder, now)
+
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	return nil
+}
+
+// remind send reminder unless its time was changed
+// after it had been planned, and plan next one.
+func (n *Notificator) remind(ctx context.Context, reminder *reminders.Reminder, now time.Time) error {
+	user, err := n.models.Users.Get(ctx, reminder.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	day, err := reminderDay(user, reminder.Code, reminder.Due)
+
+	if err != nil {
+		return err
+	}
+
+	due, err := n.due(ctx, user, reminder.Code, *day)
+
+	if err != nil {
+		return err
+	}
+
+	if due != nil && due.After(now) {
+		reminder.Due = *due
+
+		return n.models.Reminders.Set(ctx, reminder)
+	}
+
+	if due != nil && now.Sub(*due) <= maxDelay {
+		err = n.fire(ctx, user, reminder.Code, *day)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	next, err := n.next(ctx, user, reminder.Code, now)
+
+	if err != nil {
+		return err
+	}
+
+	if next == nil {
+		return nil
+	}
+
+	reminder.Due = *next
+
+	return n.models.Reminders.Set(ctx, reminder)
+}
+
+// fire send reminder if it is relevant for user day.
+func (n *Notificator) fire(ctx context.Context, user *users.User, code string, day time.Time) error {
+	if code == codeWeeklyReport {
+		return n.Send(user, code, "")
+	}
+
+	midday, err := user.Midday(day)
+
+	if err != nil {
+		return err
+	}
+
+	if code == codeNextDay {
+		nextMidday := midday.AddDate(0, 0, 1)
+		nextDayGoals, err := n.models.UserGoals.ListByUserAndDate(ctx,
+			user.ID, &nextMidday)
+
+		if err != nil {
+			return err
+		}
+
+		gTypes, err := n.models.GoalTypes.List(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if len(nextDayGoals) >= len(gTypes) {
+			return nil
+		}
+
+		return n.Send(user, code, "")
+	}
+
+	uGoals, err := n.models.UserGoals.ListByUserAndDate(ctx, user.ID, midday)
+
+	if err != nil {
+		return err
+	}
+
+	if len(uGoals) == 0 {
+		return nil
+	}
+
+	if code == codeTaskList {
+		return n.SendParams(user, code, "", map[string]interface{}{
+			"date": day.Format(dateLayout),
+		})
+	}
+
+	return n.Send(user, code, "")
+}
+
+// next get first reminder time after given time.
+func (n *Notificator) next(ctx context.Context, user *users.User, code string,
+	after time.Time) (*time.Time, error) {
+
+	day, err := user.Day(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < planDays; i++ {
+		due, err := n.due(ctx, user, code, day.AddDate(0, 0, i))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if due != nil && due.After(after) {
+			return due, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// due get reminder time of user day date, nil if there is no
+// reminder that day. By default reminders follow user awaking
+// goal, preferred hours override them.
+func (n *Notificator) due(ctx context.Context, user *users.User, code string,
+	day time.Time) (*time.Time, error) {
+
+	if code == codeWeeklyReport {
+		if day.Weekday() != time.Sunday {
+			return nil, nil
+		}
+
+		return at(user, day, weeklyReport, nil), nil
+	}
+
+	wake, err := n.wake(ctx, user, day)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch code {
+	case codeTaskList:
+		return morning(day, wake, user.Reminders.Morning), nil
+	case codeMarkTasks:
+		return at(user, day, wake+eveningOffset, user.Reminders.Evening), nil
+	case codeNextDay:
+		return at(user, day, wake+planningOffset, user.Reminders.Planning), nil
+	}
+
+	return nil, nil
+}
+
+// wake get user wake up time of day date from awaking goal.
+func (n *Notificator) wake(ctx context.Context, user *users.User, day time.Time) (int, error) {
+	midday, err := user.Midday(day)
+
+	if err != nil {
+		return 0, err
+	}
+
+	uGoals, err := n.models.UserGoals.ListByUserAndDate(ctx, user.ID, midday)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, uGoal := range uGoals {
+		if uGoal.Type != goalTypes.Awaking {
+			continue
+		}
+
+		if wake, ok := wakeTimes[uGoal.GoalID]; ok {
+			return wake, nil
+		}
+	}
+
+	return defaultWake, nil
+}
+
+// at get time of user day date, minutes are overridden by
+// preferred hour. Time before day start belongs to next date.
+func at(user *users.User, day time.Time, minutes int, hour *int) *time.Time {
+	if hour != nil {
+		minutes = *hour * 60
+	}
+
+	if minutes < user.DayStart*60 {
+		minutes += 24 * 60
+	}
+
+	t := time.Date(day.Year(), day.Month(), day.Day(), 0, minutes, 0, 0,
+		day.Location())
+
+	return &t
+}
+
+// morning get task list time of user day date. It is sent on day
+// date even before day start, so list comes before day begins.
+func morning(day time.Time, minutes int, hour *int) *time.Time {
+	if hour != nil {
+		minutes = *hour * 60
+	}
+
+	t := time.Date(day.Year(), day.Month(), day.Day(), 0, minutes, 0, 0,
+		day.Location())
+
+	return &t
+}
+
+// reminderDay get user day date of reminder due time.
+func reminderDay(user *users.User, code string, due time.Time) (*time.Time, error) {
+	if code != codeTaskList {
+		return user.Day(due)
+	}
+
+	date, err := user.Date(due)
+
+	if err != nil {
+		return nil, err
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0,
+		date.Location())
+
+	return &day, nil
+}
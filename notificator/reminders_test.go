@@ -0,0 +1,387 @@
+package notificator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/outbox"
+	"github.com/Zetkolink/oracle/models/reminders"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+const testUser = 1
+
+type testModels struct {
+	users     *users.Memory
+	userGoals *userGoals.Memory
+	outbox    *outbox.Memory
+	reminders *reminders.Memory
+}
+
+func newTestNotificator(t *testing.T, user *users.User) (*Notificator, *testModels) {
+	m := &testModels{
+		users:     users.NewMemory(),
+		userGoals: userGoals.NewMemory(),
+		outbox:    outbox.NewMemory(),
+		reminders: reminders.NewMemory(),
+	}
+
+	err := m.users.Create(context.Background(), user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := NewNotificator(Config{
+		Models: ModelsSet{
+			Users:     m.users,
+			UserGoals: m.userGoals,
+			GoalTypes: goalTypes.NewMemory(&goalTypes.GoalType{ID: goalTypes.Awaking}),
+			Outbox:    m.outbox,
+			Reminders: m.reminders,
+		},
+	})
+
+	return n, m
+}
+
+// assignGoal put user goal of type on user day date.
+func (m *testModels) assignGoal(t *testing.T, user *users.User, day time.Time,
+	gType int64, goalID int64) {
+
+	midday, err := user.Midday(day)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from, err := user.StartDate(*midday)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	to, err := user.EndDate(*midday)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.userGoals.Create(context.Background(), &userGoals.UserGoal{
+		UserID: user.ID,
+		GoalID: goalID,
+		Type:   gType,
+		Phase:  userGoals.PhasePlanning,
+		Status: userGoals.StatusSoon,
+		From:   from.UTC(),
+		To:     to.UTC(),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestUser() *users.User {
+	return &users.User{
+		ID:       testUser,
+		Timezone: "UTC",
+		DayStart: users.DefaultDayStart,
+		Active:   true,
+	}
+}
+
+func clock(day time.Time, hour, min int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, min, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	hour := func(h int) *int {
+		return &h
+	}
+
+	// 2026-05-10 is sunday.
+	day := time.Date(2026, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		code      string
+		dayStart  int
+		awaking   int
+		reminders users.Reminders
+		after     time.Time
+		want      time.Time
+	}{
+		{name: "task list by default", code: codeTaskList,
+			after: clock(day, 7, 0), want: clock(day, 12, 0)},
+		{name: "mark tasks by default", code: codeMarkTasks,
+			after: clock(day, 7, 0), want: clock(day, 20, 0)},
+		{name: "next day by default", code: codeNextDay,
+			after: clock(day, 7, 0), want: clock(day, 22, 0)},
+		{name: "weekly report on sunday", code: codeWeeklyReport,
+			after: clock(day, 7, 0), want: clock(day, 20, 0)},
+		{name: "weekly report next sunday", code: codeWeeklyReport,
+			after: clock(day, 21, 0), want: clock(day.AddDate(0, 0, 7), 20, 0)},
+		{name: "passed reminder moves to next day", code: codeTaskList,
+			after: clock(day, 13, 0), want: clock(day.AddDate(0, 0, 1), 12, 0)},
+		{name: "task list follows awaking goal", code: codeTaskList, awaking: goals.Awaking8,
+			after: clock(day, 7, 0), want: clock(day, 8, 0)},
+		{name: "mark tasks follows awaking goal", code: codeMarkTasks, awaking: goals.Awaking10,
+			after: clock(day, 7, 0), want: clock(day, 18, 0)},
+		{name: "preferred hour overrides awaking goal", code: codeTaskList, awaking: goals.Awaking8,
+			reminders: users.Reminders{Morning: hour(9)},
+			after:     clock(day, 7, 0), want: clock(day, 9, 0)},
+		{name: "preferred hour before day start is next date", code: codeNextDay,
+			reminders: users.Reminders{Planning: hour(1)},
+			after:     clock(day, 7, 0), want: clock(day.AddDate(0, 0, 1), 1, 0)},
+		{name: "task list before day start is sent on its date", code: codeTaskList, dayStart: 10,
+			reminders: users.Reminders{Morning: hour(8)},
+			after:     clock(day, 7, 0), want: clock(day, 8, 0)},
+		{name: "task list before day start after it is sent", code: codeTaskList, dayStart: 10,
+			reminders: users.Reminders{Morning: hour(8)},
+			after:     clock(day, 9, 0), want: clock(day.AddDate(0, 0, 1), 8, 0)},
+		{name: "awaking goal before day start", code: codeTaskList, dayStart: 10, awaking: goals.Awaking6,
+			after: clock(day, 5, 0), want: clock(day, 6, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newTestUser()
+			user.Reminders = tt.reminders
+
+			if tt.dayStart != 0 {
+				user.DayStart = tt.dayStart
+			}
+
+			n, m := newTestNotificator(t, user)
+
+			if tt.awaking != 0 {
+				m.assignGoal(t, user, day, goalTypes.Awaking, int64(tt.awaking))
+			}
+
+			got, err := n.next(context.Background(), user, tt.code, tt.after)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got == nil {
+				t.Fatal("no reminder planned")
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemind(t *testing.T) {
+	day := time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		awaking int
+		planned time.Time
+		now     time.Time
+		sent    int64
+		due     time.Time
+	}{
+		{name: "sent in time",
+			planned: clock(day, 12, 0), now: clock(day, 12, 0),
+			sent: 1, due: clock(day.AddDate(0, 0, 1), 12, 0)},
+		{name: "sent with delay",
+			planned: clock(day, 12, 0), now: clock(day, 12, 59),
+			sent: 1, due: clock(day.AddDate(0, 0, 1), 12, 0)},
+		{name: "skipped after max delay",
+			planned: clock(day, 12, 0), now: clock(day, 13, 1),
+			sent: 0, due: clock(day.AddDate(0, 0, 1), 12, 0)},
+		{name: "moved later by awaking goal", awaking: goals.Awaking10,
+			planned: clock(day, 8, 0), now: clock(day, 8, 0),
+			sent: 0, due: clock(day, 10, 0)},
+		// Goal set after planning, PlanUser must be called to move it.
+		{name: "skipped when awaking goal moved it earlier", awaking: goals.Awaking6,
+			planned: clock(day, 12, 0), now: clock(day, 12, 0),
+			sent: 0, due: clock(day.AddDate(0, 0, 1), 12, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newTestUser()
+			n, m := newTestNotificator(t, user)
+			ctx := context.Background()
+
+			// Task list is sent only for days with goals.
+			m.assignGoal(t, user, day, goalTypes.Awaking+1, 100)
+
+			if tt.awaking != 0 {
+				m.assignGoal(t, user, day, goalTypes.Awaking, int64(tt.awaking))
+			}
+
+			err := n.remind(ctx, &reminders.Reminder{
+				UserID: user.ID,
+				Code:   codeTaskList,
+				Due:    tt.planned,
+			}, tt.now)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sent, err := m.outbox.Count(ctx, outbox.Filter{UserID: user.ID})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if sent != tt.sent {
+				t.Errorf("sent %d, want %d", sent, tt.sent)
+			}
+
+			reminder, err := m.reminders.Get(ctx, user.ID, codeTaskList)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if reminder == nil {
+				t.Fatal("next reminder is not planned")
+			}
+
+			if !reminder.Due.Equal(tt.due) {
+				t.Errorf("due = %s, want %s", reminder.Due, tt.due)
+			}
+		})
+	}
+}
+
+func TestRemindWithoutGoals(t *testing.T) {
+	user := newTestUser()
+	n, m := newTestNotificator(t, user)
+	ctx := context.Background()
+	day := time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC)
+
+	err := n.remind(ctx, &reminders.Reminder{
+		UserID: user.ID,
+		Code:   codeTaskList,
+		Due:    clock(day, 12, 0),
+	}, clock(day, 12, 0))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent, err := m.outbox.Count(ctx, outbox.Filter{UserID: user.ID})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent != 0 {
+		t.Errorf("sent %d, want none for day without goals", sent)
+	}
+}
+
+func TestPlanUser(t *testing.T) {
+	user := newTestUser()
+	n, m := newTestNotificator(t, user)
+	ctx := context.Background()
+
+	// Due reminder is left to Notify.
+	due := &reminders.Reminder{
+		UserID: user.ID,
+		Code:   codeMarkTasks,
+		Due:    time.Now().Add(-time.Minute).Truncate(time.Second),
+	}
+
+	err := m.reminders.Set(ctx, due)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = n.Plan(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	for _, code := range reminderCodes {
+		reminder, err := m.reminders.Get(ctx, user.ID, code)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reminder == nil {
+			t.Errorf("%s is not planned", code)
+			continue
+		}
+
+		if code == codeMarkTasks {
+			if !reminder.Due.Equal(due.Due) {
+				t.Errorf("%s due = %s, want kept %s", code, reminder.Due, due.Due)
+			}
+
+			continue
+		}
+
+		if !reminder.Due.After(now.Add(-time.Second)) {
+			t.Errorf("%s due = %s is in past", code, reminder.Due)
+		}
+	}
+}
+
+// Task list of day starting after morning reminder hour must be sent
+// before that day with its goals, not at the end of it.
+func TestRemindBeforeDayStart(t *testing.T) {
+	hour := 8
+	user := newTestUser()
+	user.DayStart = 10
+	user.Reminders = users.Reminders{Morning: &hour}
+	n, m := newTestNotificator(t, user)
+	ctx := context.Background()
+	day := time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC)
+
+	m.assignGoal(t, user, day, goalTypes.Awaking+1, 100)
+
+	err := n.remind(ctx, &reminders.Reminder{
+		UserID: user.ID,
+		Code:   codeTaskList,
+		Due:    clock(day, 8, 0),
+	}, clock(day, 8, 0))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := m.outbox.ListDue(ctx, time.Now().Add(time.Hour), 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msgs) != 1 {
+		t.Fatalf("sent %d, want 1", len(msgs))
+	}
+
+	if date := msgs[0].Params["date"]; date != "2026-05-11" {
+		t.Errorf("list date = %v, want 2026-05-11", date)
+	}
+
+	reminder, err := m.reminders.Get(ctx, user.ID, codeTaskList)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := clock(day.AddDate(0, 0, 1), 8, 0); reminder == nil || !reminder.Due.Equal(want) {
+		t.Errorf("next reminder = %v, want %s", reminder, want)
+	}
+}
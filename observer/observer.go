@@ -5,41 +5,75 @@ import (
 	"log"
 	"time"
 
+	"github.com/Zetkolink/oracle/judge"
+	"github.com/Zetkolink/oracle/manager"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/templates"
 	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/scorer"
+	"github.com/Zetkolink/oracle/streaker"
 )
 
 type Observer struct {
-	models ModelsSet
+	models   ModelsSet
+	scorer   *scorer.Scorer
+	streaker *streaker.Streaker
+	judge    *judge.Judge
+	manager  *manager.Manager
 }
 
 type Config struct {
-	Models ModelsSet
+	Models   ModelsSet
+	Scorer   *scorer.Scorer
+	Streaker *streaker.Streaker
+	Judge    *judge.Judge
+	Manager  *manager.Manager
 }
 
 type ModelsSet struct {
-	UserGoals *userGoals.Model
+	Users     users.Repository
+	GoalTypes goalTypes.Repository
+	UserGoals userGoals.Repository
+	Templates templates.Repository
 }
 
 func NewObserver(config Config) *Observer {
-	return &Observer{models: config.Models}
+	return &Observer{
+		models:   config.Models,
+		scorer:   config.Scorer,
+		streaker: config.Streaker,
+		judge:    config.Judge,
+		manager:  config.Manager,
+	}
 }
 
-func (o *Observer) Run() {
-	go func() {
-		err := o.UpdateActive(context.Background())
+// Run update user goals phases, materialise templates
+// and decide overdue reviews. Every step runs even
+// if previous failed, first error is returned.
+func (o *Observer) Run(ctx context.Context) error {
+	steps := []func(ctx context.Context) error{
+		o.UpdateTemplates,
+		o.UpdateActive,
+		o.UpdatePlanning,
+		o.judge.Run,
+	}
 
-		if err != nil {
-			log.Println(err)
-		}
+	var firstErr error
 
-		err = o.UpdatePlanning(context.Background())
+	for _, step := range steps {
+		err := step(ctx)
 
 		if err != nil {
-			log.Println(err)
+			if firstErr == nil {
+				firstErr = err
+			} else {
+				log.Println(err)
+			}
 		}
+	}
 
-		time.Sleep(1 * time.Hour)
-	}()
+	return firstErr
 }
 
 func (o *Observer) UpdatePlanning(ctx context.Context) error {
@@ -78,6 +112,26 @@ func (o *Observer) UpdateActive(ctx context.Context) error {
 
 	for _, uGoal := range uGoals {
 		if time.Now().UTC().After(uGoal.To) {
+			reviewed := false
+
+			// Review is opened before goal is finished, so goal
+			// stays active and is retried if review is not opened.
+			if uGoal.Status == userGoals.StatusComplete {
+				err := o.judge.Open(ctx, uGoal)
+
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+
+				reviewed, err = o.evaluated(ctx, uGoal)
+
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+			}
+
 			err := o.models.UserGoals.UpdatePhase(ctx, uGoal.ID,
 				userGoals.PhaseFinished)
 
@@ -85,6 +139,8 @@ func (o *Observer) UpdateActive(ctx context.Context) error {
 				return err
 			}
 
+			uGoal.Phase = userGoals.PhaseFinished
+
 			if uGoal.Status == userGoals.StatusInProgress {
 				err := o.models.UserGoals.UpdateStatus(ctx, uGoal.ID,
 					userGoals.StatusFailed)
@@ -92,7 +148,67 @@ func (o *Observer) UpdateActive(ctx context.Context) error {
 				if err != nil {
 					return err
 				}
+
+				uGoal.Status = userGoals.StatusFailed
+			}
+
+			// Reviewed goal is scored and tracked by judge verdict.
+			if reviewed {
+				continue
 			}
+
+			err = o.scorer.Score(ctx, uGoal)
+
+			if err != nil {
+				log.Println(err)
+			}
+
+			err = o.streaker.Track(ctx, uGoal)
+
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// evaluated check user goal type is evaluated by peers.
+func (o *Observer) evaluated(ctx context.Context, uGoal *userGoals.UserGoal) (bool, error) {
+	gType, err := o.models.GoalTypes.Get(ctx, uGoal.Type)
+
+	if err != nil {
+		return false, err
+	}
+
+	return gType.Evaluated, nil
+}
+
+// UpdateTemplates materialise active templates into user goals ahead of time.
+func (o *Observer) UpdateTemplates(ctx context.Context) error {
+	list, err := o.models.Templates.ListActive(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tpl := range list {
+		user, err := o.models.Users.Get(ctx, tpl.UserID)
+
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if user == nil || !user.Active {
+			continue
+		}
+
+		err = o.manager.Materialize(ctx, user, tpl)
+
+		if err != nil {
+			log.Println(err)
 		}
 	}
 
@@ -0,0 +1,211 @@
+package observer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Zetkolink/oracle/judge"
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/forRate"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/outbox"
+	"github.com/Zetkolink/oracle/models/proofs"
+	"github.com/Zetkolink/oracle/models/reviews"
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/streaks"
+	"github.com/Zetkolink/oracle/models/templates"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+	"github.com/Zetkolink/oracle/scorer"
+	"github.com/Zetkolink/oracle/streaker"
+)
+
+const (
+	typePlain     = 1
+	typeEvaluated = 2
+)
+
+type testModels struct {
+	userGoals *userGoals.Memory
+	reviews   *reviews.Memory
+	scores    *scores.Memory
+	streaks   *streaks.Memory
+}
+
+func newTestObserver(t *testing.T) (*Observer, *testModels) {
+	ctx := context.Background()
+	m := &testModels{
+		userGoals: userGoals.NewMemory(),
+		reviews:   reviews.NewMemory(),
+		scores:    scores.NewMemory(),
+		streaks:   streaks.NewMemory(),
+	}
+
+	usersModel := users.NewMemory()
+
+	for id := int64(1); id <= 4; id++ {
+		err := usersModel.Create(ctx, &users.User{
+			ID:       id,
+			Timezone: "UTC",
+			DayStart: users.DefaultDayStart,
+			Active:   true,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	typesModel := goalTypes.NewMemory(
+		&goalTypes.GoalType{ID: typePlain, Points: 10},
+		&goalTypes.GoalType{ID: typeEvaluated, Points: 20, Evaluated: true},
+	)
+	evalModel := evaluations.NewMemory()
+	nt := notificator.NewNotificator(notificator.Config{
+		Models: notificator.ModelsSet{
+			Users:  usersModel,
+			Outbox: outbox.NewMemory(),
+		},
+	})
+
+	sc := scorer.NewScorer(scorer.Config{
+		Models: scorer.ModelsSet{
+			Users:       usersModel,
+			GoalTypes:   typesModel,
+			Evaluations: evalModel,
+			Scores:      m.scores,
+		},
+	})
+
+	sk := streaker.NewStreaker(streaker.Config{
+		Models: streaker.ModelsSet{
+			Users:     usersModel,
+			GoalTypes: typesModel,
+			UserGoals: m.userGoals,
+			Streaks:   m.streaks,
+		},
+		Notificator: nt,
+	})
+
+	jg := judge.NewJudge(judge.Config{
+		Models: judge.ModelsSet{
+			Users:       usersModel,
+			GoalTypes:   typesModel,
+			Goals:       goals.NewMemory(),
+			UserGoals:   m.userGoals,
+			Evaluations: evalModel,
+			ForRate:     forRate.NewMemory(evalModel),
+			Reviews:     m.reviews,
+			Proofs:      proofs.NewMemory(),
+		},
+		Scorer:      sc,
+		Streaker:    sk,
+		Notificator: nt,
+	})
+
+	o := NewObserver(Config{
+		Models: ModelsSet{
+			Users:     usersModel,
+			GoalTypes: typesModel,
+			UserGoals: m.userGoals,
+			Templates: templates.NewMemory(),
+		},
+		Scorer:   sc,
+		Streaker: sk,
+		Judge:    jg,
+	})
+
+	return o, m
+}
+
+// Evaluated goals earn points and streak only by review verdict.
+func TestUpdateActive(t *testing.T) {
+	day := time.Now().UTC().AddDate(0, 0, -2)
+	from := time.Date(day.Year(), day.Month(), day.Day(), users.DefaultDayStart, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		gType    int64
+		reviewed bool
+		points   int64
+		streak   int64
+	}{
+		{name: "plain goal", gType: typePlain, points: 10, streak: 1},
+		{name: "evaluated goal before verdict", gType: typeEvaluated, reviewed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, m := newTestObserver(t)
+			ctx := context.Background()
+
+			uGoal := &userGoals.UserGoal{
+				UserID: 1,
+				GoalID: 1,
+				Type:   tt.gType,
+				Phase:  userGoals.PhaseActive,
+				Status: userGoals.StatusComplete,
+				From:   from,
+				To:     from.Add(24*time.Hour - time.Second),
+			}
+
+			err := m.userGoals.Create(ctx, uGoal)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = o.UpdateActive(ctx)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := m.userGoals.Get(ctx, uGoal.ID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Phase != userGoals.PhaseFinished {
+				t.Errorf("phase = %s, want %s", got.Phase, userGoals.PhaseFinished)
+			}
+
+			_, err = m.reviews.Get(ctx, uGoal.ID)
+
+			if reviewed := err != sql.ErrNoRows; reviewed != tt.reviewed {
+				t.Errorf("review opened %t, want %t (err %v)", reviewed, tt.reviewed, err)
+			}
+
+			points, err := m.scores.Get(ctx, 1, scores.PeriodDay, from)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if points != tt.points {
+				t.Errorf("points = %d, want %d", points, tt.points)
+			}
+
+			var current int64
+
+			streak, err := m.streaks.Get(ctx, 1, tt.gType)
+
+			if err != nil && err != sql.ErrNoRows {
+				t.Fatal(err)
+			}
+
+			if streak != nil {
+				current = streak.Current
+			}
+
+			if current != tt.streak {
+				t.Errorf("streak = %d, want %d", current, tt.streak)
+			}
+		})
+	}
+}
@@ -3,22 +3,49 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
-
+	"time"
+
+	"github.com/Zetkolink/oracle/admin"
+	"github.com/Zetkolink/oracle/eraser"
+	"github.com/Zetkolink/oracle/exporter"
+	"github.com/Zetkolink/oracle/judge"
+	"github.com/Zetkolink/oracle/leader"
+	"github.com/Zetkolink/oracle/locator"
 	"github.com/Zetkolink/oracle/manager"
+	"github.com/Zetkolink/oracle/models/aliases"
 	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/events"
 	"github.com/Zetkolink/oracle/models/forRate"
 	"github.com/Zetkolink/oracle/models/goalTypes"
 	"github.com/Zetkolink/oracle/models/goals"
 	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/outbox"
+	"github.com/Zetkolink/oracle/models/proofs"
+	"github.com/Zetkolink/oracle/models/reminders"
+	"github.com/Zetkolink/oracle/models/reviews"
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/streaks"
+	"github.com/Zetkolink/oracle/models/templates"
 	"github.com/Zetkolink/oracle/models/userGoals"
 	"github.com/Zetkolink/oracle/models/users"
 	"github.com/Zetkolink/oracle/models/whiteList"
 	"github.com/Zetkolink/oracle/notificator"
 	"github.com/Zetkolink/oracle/observer"
+	"github.com/Zetkolink/oracle/pseudonym"
 	"github.com/Zetkolink/oracle/rater"
+	"github.com/Zetkolink/oracle/scheduler"
+	"github.com/Zetkolink/oracle/scorer"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/delivery"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/telegram"
 	"github.com/Zetkolink/oracle/services/vk"
+	"github.com/Zetkolink/oracle/statistics"
+	"github.com/Zetkolink/oracle/streaker"
 	"github.com/go-redis/redis/v8"
 	vkSDK "github.com/go-vk-api/vk"
 	_ "github.com/lib/pq"
@@ -27,11 +54,25 @@ import (
 
 type oracle struct {
 	db          *sql.DB
+	redisClient *redis.Client
 	models      modelSet
 	vk          *vk.Service
+	telegram    *telegram.Service
+	admin       *admin.Server
 	observer    *observer.Observer
 	notificator *notificator.Notificator
-	mapsClient  *maps.Client
+	manager     *manager.Manager
+	rater       *rater.Rater
+	scorer      *scorer.Scorer
+	judge       *judge.Judge
+	pseudonym   *pseudonym.Pseudonym
+	statistics  *statistics.Statistics
+	exporter    *exporter.Exporter
+	eraser      *eraser.Eraser
+	pool        *pool.Pool
+	locator     *locator.Locator
+	scheduler   *scheduler.Scheduler
+	elector     *leader.Elector
 	wg          sync.WaitGroup
 }
 
@@ -42,14 +83,40 @@ type modelSet struct {
 	userGoals   *userGoals.Model
 	evaluations *evaluations.Model
 	whiteList   *whiteList.Model
+	keyboards   *keyboards.Model
+	scores      *scores.Model
+	reviews     *reviews.Model
+	proofs      *proofs.Model
+	aliases     *aliases.Model
+	templates   *templates.Model
+	streaks     *streaks.Model
+	reminders   *reminders.Model
 }
 
+const (
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+var (
+	errShutdownTimeout = errors.New("shutdown timeout exceeded")
+	errNoTransport     = errors.New("no transport enabled")
+	errNoPseudonym     = errors.New("config Pseudonym.Secret (pseudonym.secret) is not set, see README")
+)
+
 type config struct {
-	Handlers    map[string]bool
-	TimezoneAPI timezoneAPIConfig
-	Db          dbConfig
-	Vk          vkConfig
-	Cache       cacheConfig
+	Handlers        map[string]bool
+	TimezoneAPI     timezoneAPIConfig
+	Db              dbConfig
+	Vk              vkConfig
+	Telegram        telegramConfig
+	Cache           cacheConfig
+	Pool            poolConfig
+	Admin           adminConfig
+	Evaluation      evaluationConfig
+	Pseudonym       pseudonymConfig
+	Scheduler       schedulerConfig
+	Leader          leaderConfig
+	ShutdownTimeout int
 }
 
 type timezoneAPIConfig struct {
@@ -58,6 +125,55 @@ type timezoneAPIConfig struct {
 
 type vkConfig struct {
 	Token string
+	// Mode inbound mode, "longpoll" or "callback".
+	Mode     string
+	Callback vkCallbackConfig
+}
+
+type vkCallbackConfig struct {
+	Addr         string
+	Path         string
+	Confirmation string
+	Secret       string
+	GroupID      int64
+}
+
+type telegramConfig struct {
+	Token string
+	URL   string
+}
+
+type adminConfig struct {
+	Addr  string
+	Token string
+}
+
+type evaluationConfig struct {
+	Raters   int
+	Quorum   int64
+	Deadline int
+}
+
+type pseudonymConfig struct {
+	Secret string
+}
+
+type schedulerConfig struct {
+	// Jobs schedules overriding defaults by job name,
+	// cron expression or "@every <duration>".
+	Jobs map[string]string
+}
+
+type leaderConfig struct {
+	// ID instance ID, host name and PID by default.
+	ID string
+	// TTL leader lease time in seconds.
+	TTL int
+}
+
+type poolConfig struct {
+	Size      int
+	QueueSize int
 }
 
 type dbConfig struct {
@@ -66,6 +182,7 @@ type dbConfig struct {
 	User     string
 	Password string
 	Database string
+	Migrate  bool
 }
 
 type cacheConfig struct {
@@ -74,6 +191,10 @@ type cacheConfig struct {
 }
 
 func newOracle() (*oracle, error) {
+	if cfg.Pseudonym.Secret == "" {
+		return nil, errNoPseudonym
+	}
+
 	db, err := sql.Open("postgres", cfg.Db.GetConn())
 
 	if err != nil {
@@ -86,6 +207,14 @@ func newOracle() (*oracle, error) {
 		return nil, err
 	}
 
+	if cfg.Db.Migrate {
+		err = migrateUp(context.Background(), db)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	rdb := redis.NewClient(cfg.Cache.GetOptions())
 
 	err = rdb.Ping(context.Background()).Err()
@@ -94,12 +223,8 @@ func newOracle() (*oracle, error) {
 		return nil, err
 	}
 
-	vkClient, err := vkSDK.NewClientWithOptions(
-		vkSDK.WithToken(cfg.Vk.Token),
-	)
-
-	if err != nil {
-		return nil, err
+	if !vkEnabled(cfg.Handlers) && !cfg.Handlers["telegram"] {
+		return nil, errNoTransport
 	}
 
 	usersModel, err := users.NewModel(
@@ -166,6 +291,89 @@ func newOracle() (*oracle, error) {
 		return nil, err
 	}
 
+	scoresModel, err := scores.NewModel(
+		scores.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsModel, err := reviews.NewModel(
+		reviews.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	proofsModel, err := proofs.NewModel(
+		proofs.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aliasesModel, err := aliases.NewModel(
+		aliases.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	templatesModel, err := templates.NewModel(
+		templates.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	streaksModel, err := streaks.NewModel(
+		streaks.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	outboxModel, err := outbox.NewModel(
+		outbox.ModelConfig{Db: db},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	remindersModel, err := reminders.NewModel(
+		reminders.ModelConfig{Cache: rdb},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	eventsModel, err := events.NewModel(
+		events.ModelConfig{Cache: rdb},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := pseudonym.NewPseudonym(pseudonym.Config{
+		Secret: cfg.Pseudonym.Secret,
+		Models: pseudonym.ModelsSet{
+			Aliases: aliasesModel,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
 	mapsClient, err := maps.NewClient(
 		maps.WithAPIKey(cfg.TimezoneAPI.Token),
 	)
@@ -174,62 +382,225 @@ func newOracle() (*oracle, error) {
 		return nil, err
 	}
 
+	sc := scorer.NewScorer(scorer.Config{
+		Models: scorer.ModelsSet{
+			Users:       usersModel,
+			GoalTypes:   typesModel,
+			Evaluations: evalModel,
+			Scores:      scoresModel,
+		}},
+	)
+
+	rt := rater.NewRater(rater.Config{
+		Models: rater.ModelsSet{
+			Goals:     goalsModel,
+			UserGoals: userGoalsModel,
+			ForRate:   forRateModel,
+		},
+		Pseudonym: ps,
+	})
+
+	workers := pool.NewPool(pool.Config{
+		Size:      cfg.Pool.Size,
+		QueueSize: cfg.Pool.QueueSize,
+	})
+
+	var (
+		vkService  *vk.Service
+		deliverers []notificator.Deliverer
+	)
+
+	if vkEnabled(cfg.Handlers) {
+		vkClient, err := vkSDK.NewClientWithOptions(
+			vkSDK.WithToken(cfg.Vk.Token),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		vkService = vk.NewService(vk.Config{
+			VKClient: vkClient,
+			Pool:     workers,
+			Mode:     cfg.Vk.Mode,
+			Callback: vk.CallbackConfig{
+				Addr:         cfg.Vk.Callback.Addr,
+				Path:         cfg.Vk.Callback.Path,
+				Confirmation: cfg.Vk.Callback.Confirmation,
+				Secret:       cfg.Vk.Callback.Secret,
+				GroupID:      cfg.Vk.Callback.GroupID,
+			},
+			Models: vk.ModelsSet{
+				Users:     usersModel,
+				Keyboards: keyboardsModel,
+				Events:    eventsModel,
+			},
+		})
+
+		deliverers = append(deliverers, delivery.NewDelivery(delivery.Config{
+			Transport: services.TransportVK,
+			Sender:    vkService,
+			Router:    vkService.Router(),
+			Pool:      workers,
+			Models: delivery.ModelsSet{
+				Users:     usersModel,
+				Keyboards: keyboardsModel,
+			},
+		}))
+	}
+
+	var tgService *telegram.Service
+
+	if cfg.Handlers["telegram"] {
+		tgService = telegram.NewService(telegram.Config{
+			Token:       cfg.Telegram.Token,
+			URL:         cfg.Telegram.URL,
+			RedisClient: rdb,
+			Pool:        workers,
+			Models: telegram.ModelsSet{
+				Users: usersModel,
+			},
+		})
+
+		deliverers = append(deliverers, delivery.NewDelivery(delivery.Config{
+			Transport: services.TransportTelegram,
+			Sender:    tgService,
+			Router:    tgService.Router(),
+			Pool:      workers,
+			Models: delivery.ModelsSet{
+				Users:     usersModel,
+				Keyboards: keyboardsModel,
+			},
+		}))
+	}
+
+	nt := notificator.NewNotificator(notificator.Config{
+		Deliverers: deliverers,
+		Models: notificator.ModelsSet{
+			Users:     usersModel,
+			UserGoals: userGoalsModel,
+			GoalTypes: typesModel,
+			Outbox:    outboxModel,
+			Reminders: remindersModel,
+		},
+	})
+
 	mg := manager.NewManager(manager.Config{
+		Notificator: nt,
 		Models: manager.ModelsSet{
 			Users:     usersModel,
 			Goals:     goalsModel,
 			GoalTypes: typesModel,
 			UserGoals: userGoalsModel,
+			Templates: templatesModel,
 		}},
 	)
 
-	rt := rater.NewRater(rater.Config{
-		Models: rater.ModelsSet{
+	sk := streaker.NewStreaker(streaker.Config{
+		Models: streaker.ModelsSet{
+			Users:     usersModel,
+			GoalTypes: typesModel,
+			UserGoals: userGoalsModel,
+			Streaks:   streaksModel,
+		},
+		Notificator: nt,
+	})
+
+	jg := judge.NewJudge(judge.Config{
+		Models: judge.ModelsSet{
+			Users:       usersModel,
+			GoalTypes:   typesModel,
 			Goals:       goalsModel,
 			UserGoals:   userGoalsModel,
 			Evaluations: evalModel,
 			ForRate:     forRateModel,
-		}},
-	)
+			Reviews:     reviewsModel,
+			Proofs:      proofsModel,
+		},
+		Scorer:      sc,
+		Streaker:    sk,
+		Notificator: nt,
+		Raters:      cfg.Evaluation.Raters,
+		Quorum:      cfg.Evaluation.Quorum,
+		Deadline:    time.Duration(cfg.Evaluation.Deadline) * time.Hour,
+	})
 
 	obs := observer.NewObserver(observer.Config{
 		Models: observer.ModelsSet{
-			UserGoals: userGoalsModel,
-		}},
-	)
-
-	nt := notificator.NewNotificator(notificator.Config{
-		Models: notificator.ModelsSet{
 			Users:     usersModel,
-			UserGoals: userGoalsModel,
 			GoalTypes: typesModel,
+			UserGoals: userGoalsModel,
+			Templates: templatesModel,
 		},
-		RedisClient: rdb,
+		Scorer:   sc,
+		Streaker: sk,
+		Judge:    jg,
+		Manager:  mg,
 	})
 
-	vkService := vk.NewService(vk.Config{
-		VKClient:   vkClient,
-		MapsClient: mapsClient,
-		Models: vk.ModelsSet{
-			Users:     usersModel,
+	stat := statistics.NewStatistics(statistics.Config{
+		Models: statistics.ModelsSet{
 			UserGoals: userGoalsModel,
-			WhiteList: whiteListModel,
-			Keyboards: keyboardsModel,
-			GoalTypes: typesModel,
-			Goals:     goalsModel,
 		},
-		Manager:     mg,
-		Rater:       rt,
+	})
+
+	ex := exporter.NewExporter(exporter.Config{
+		Models: exporter.ModelsSet{
+			Users:       usersModel,
+			GoalTypes:   typesModel,
+			Goals:       goalsModel,
+			UserGoals:   userGoalsModel,
+			Evaluations: evalModel,
+		},
+	})
+
+	er := eraser.NewEraser(eraser.Config{
+		Models: eraser.ModelsSet{
+			Users:       usersModel,
+			UserGoals:   userGoalsModel,
+			Evaluations: evalModel,
+			ForRate:     forRateModel,
+			Aliases:     aliasesModel,
+			Streaks:     streaksModel,
+			Scores:      scoresModel,
+			Reminders:   remindersModel,
+		},
 		RedisClient: rdb,
-		Notificator: nt,
+	})
+
+	sch, err := newScheduler(obs, nt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	el := leader.NewElector(leader.Config{
+		RedisClient: rdb,
+		ID:          cfg.Leader.ID,
+		TTL:         time.Duration(cfg.Leader.TTL) * time.Second,
 	})
 
 	a := oracle{
 		db:          db,
+		redisClient: rdb,
 		vk:          vkService,
+		telegram:    tgService,
 		observer:    obs,
-		mapsClient:  mapsClient,
+		locator: locator.NewLocator(locator.Config{
+			MapsClient: mapsClient,
+		}),
 		notificator: nt,
+		manager:     mg,
+		rater:       rt,
+		scorer:      sc,
+		judge:       jg,
+		pseudonym:   ps,
+		statistics:  stat,
+		exporter:    ex,
+		eraser:      er,
+		pool:        workers,
+		scheduler:   sch,
+		elector:     el,
 		models: modelSet{
 			users:       usersModel,
 			goalTypes:   typesModel,
@@ -237,27 +608,218 @@ func newOracle() (*oracle, error) {
 			userGoals:   userGoalsModel,
 			evaluations: evalModel,
 			whiteList:   whiteListModel,
+			keyboards:   keyboardsModel,
+			scores:      scoresModel,
+			reviews:     reviewsModel,
+			proofs:      proofsModel,
+			aliases:     aliasesModel,
+			templates:   templatesModel,
+			streaks:     streaksModel,
+			reminders:   remindersModel,
 		},
 	}
 
+	if vkService != nil {
+		a.registerScreens(vkService.Router(), vkService)
+	}
+
+	if tgService != nil {
+		a.registerScreens(tgService.Router(), tgService)
+	}
+
+	if cfg.Handlers["admin"] {
+		a.admin, err = admin.NewServer(admin.Config{
+			Addr:  cfg.Admin.Addr,
+			Token: cfg.Admin.Token,
+			Models: admin.ModelsSet{
+				GoalTypes: typesModel,
+				Goals:     goalsModel,
+				WhiteList: whiteListModel,
+				Outbox:    outboxModel,
+			},
+			Pseudonym: ps,
+			Exporter:  ex,
+			Scheduler: sch,
+			Elector:   el,
+			Pool:      workers,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &a, nil
 }
 
-func (o *oracle) Run() error {
-	err := o.vk.Listen()
+// vkEnabled check vk transport is enabled. It is enabled
+// unless switched off explicitly by "vk" handler.
+func vkEnabled(handlers map[string]bool) bool {
+	enabled, ok := handlers["vk"]
 
-	if err != nil {
-		return err
+	return !ok || enabled
+}
+
+func (o *oracle) Run(ctx context.Context) error {
+	// VK delivers callback events to any instance.
+	if o.vk != nil && o.vk.Mode() == vk.ModeCallback {
+		err := o.vk.Listen(ctx)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.admin != nil {
+		err := o.admin.Listen(ctx)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	o.wg.Add(1)
+
+	go func() {
+		defer o.wg.Done()
+		o.elector.Run(ctx, o.lead)
+	}()
+
+	return nil
+}
+
+// lead run work which must be done by single instance: update
+// listeners and scheduled jobs. It returns when ctx is done.
+func (o *oracle) lead(ctx context.Context) error {
+	if o.vk != nil && o.vk.Mode() != vk.ModeCallback {
+		err := o.vk.Listen(ctx)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.telegram != nil {
+		err := o.telegram.Listen(ctx)
+
+		if err != nil {
+			return err
+		}
 	}
 
-	o.observer.Run()
-	o.notificator.Run()
+	o.scheduler.Start(ctx)
+	<-ctx.Done()
+
+	o.scheduler.Wait()
+
+	if o.vk != nil && o.vk.Mode() != vk.ModeCallback {
+		o.vk.Wait()
+	}
+
+	if o.telegram != nil {
+		o.telegram.Wait()
+	}
 
 	return nil
 }
 
-func (o *oracle) Stop() {
-	o.wg.Wait()
+// Stop wait in-flight work to finish and close connections.
+// Context passed to Run must be cancelled before.
+func (o *oracle) Stop() error {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		o.pool.Stop()
+		o.wg.Wait()
+
+		if o.vk != nil {
+			o.vk.Wait()
+		}
+
+		if o.admin != nil {
+			o.admin.Wait()
+		}
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+	case <-time.After(cfg.GetShutdownTimeout()):
+		err = errShutdownTimeout
+	}
+
+	closeErr := o.redisClient.Close()
+
+	if closeErr != nil {
+		log.Println(closeErr)
+	}
+
+	closeErr = o.db.Close()
+
+	if closeErr != nil {
+		log.Println(closeErr)
+	}
+
+	return err
+}
+
+// newScheduler register background jobs, schedules
+// can be overridden in config.
+func newScheduler(obs *observer.Observer, nt *notificator.Notificator) (*scheduler.Scheduler, error) {
+	sch := scheduler.NewScheduler()
+
+	jobs := []struct {
+		name      string
+		schedule  string
+		jitter    time.Duration
+		immediate bool
+		run       func(ctx context.Context) error
+	}{
+		{"observer", "@every 5m", 30 * time.Second, true, obs.Run},
+		{"reminders", "0 * * * *", time.Minute, true, nt.Plan},
+		{"notify", "* * * * *", 0, false, nt.Notify},
+		{"outbox", "@every 10s", 0, false, nt.Dispatch},
+	}
+
+	for _, job := range jobs {
+		spec, ok := cfg.Scheduler.Jobs[job.name]
+
+		if !ok {
+			spec = job.schedule
+		}
+
+		schedule, err := scheduler.Parse(spec)
+
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.name, err)
+		}
+
+		err = sch.Register(scheduler.Job{
+			Name:      job.name,
+			Schedule:  schedule,
+			Jitter:    job.jitter,
+			Immediate: job.immediate,
+			Run:       job.run,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sch, nil
+}
+
+// GetShutdownTimeout get time given to finish in-flight work.
+func (c *config) GetShutdownTimeout() time.Duration {
+	if c.ShutdownTimeout <= 0 {
+		return defaultShutdownTimeout
+	}
+
+	return time.Duration(c.ShutdownTimeout) * time.Second
 }
 
 func (d *dbConfig) GetConn() string {
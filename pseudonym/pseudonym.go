@@ -0,0 +1,134 @@
+package pseudonym
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/Zetkolink/oracle/models/aliases"
+)
+
+var (
+	// ErrNoSecret secret not configured.
+	ErrNoSecret = errors.New("pseudonym secret required")
+
+	adjectives = []string{
+		"Тихий", "Смелый", "Быстрый", "Мудрый", "Весёлый", "Ловкий",
+		"Добрый", "Хитрый", "Звёздный", "Северный", "Ясный", "Бодрый",
+		"Упорный", "Скромный", "Яркий", "Спокойный",
+	}
+
+	nouns = []string{
+		"Барсук", "Ёж", "Филин", "Лис", "Медведь", "Волк",
+		"Бобр", "Енот", "Кит", "Орёл", "Тюлень", "Рысь",
+		"Олень", "Заяц", "Сокол", "Пингвин",
+	}
+)
+
+// Pseudonym stable aliases of users shown to other users.
+// Each viewer sees own alias of subject, so viewers can't match
+// aliases with each other, aliases are rotated by generation.
+type Pseudonym struct {
+	secret []byte
+	models ModelsSet
+}
+
+type Config struct {
+	Secret string
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	Aliases aliases.Repository
+}
+
+func NewPseudonym(config Config) (*Pseudonym, error) {
+	if config.Secret == "" {
+		return nil, ErrNoSecret
+	}
+
+	return &Pseudonym{
+		secret: []byte(config.Secret),
+		models: config.Models,
+	}, nil
+}
+
+// Alias get alias of subject user shown to viewer user.
+func (p *Pseudonym) Alias(ctx context.Context, viewerID int64, subjectID int64) (string, error) {
+	alias, err := p.models.Aliases.Get(ctx, viewerID, subjectID)
+
+	if err == nil {
+		return alias.Alias, nil
+	}
+
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+
+	alias = &aliases.Alias{
+		ViewerID:   viewerID,
+		SubjectID:  subjectID,
+		Generation: 1,
+	}
+
+	alias.Alias = p.generate(alias)
+	err = p.models.Aliases.Create(ctx, alias)
+
+	if err != nil {
+		return "", err
+	}
+
+	alias, err = p.models.Aliases.Get(ctx, viewerID, subjectID)
+
+	if err != nil {
+		return "", err
+	}
+
+	return alias.Alias, nil
+}
+
+// Rotate replace aliases of subject with new ones, all if subjectID is 0.
+// Returns number of rotated aliases.
+func (p *Pseudonym) Rotate(ctx context.Context, subjectID int64) (int64, error) {
+	list, err := p.models.Aliases.ListBySubject(ctx, subjectID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var rotated int64
+
+	for _, alias := range list {
+		alias.Generation++
+		alias.Alias = p.generate(alias)
+
+		err = p.models.Aliases.Update(ctx, alias)
+
+		if err != nil {
+			return rotated, err
+		}
+
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+// generate derive alias from secret, so it can't be reversed
+// to user ID without the secret.
+func (p *Pseudonym) generate(alias *aliases.Alias) string {
+	mac := hmac.New(sha256.New, p.secret)
+
+	_, _ = fmt.Fprintf(mac, "%d:%d:%d", alias.ViewerID, alias.SubjectID, alias.Generation)
+	sum := mac.Sum(nil)
+
+	adjective := adjectives[int(sum[0])%len(adjectives)]
+	noun := nouns[int(sum[1])%len(nouns)]
+	number := binary.BigEndian.Uint16(sum[2:4]) % 1000
+
+	return fmt.Sprintf("%s %s %03d", adjective, noun, number)
+}
@@ -2,6 +2,7 @@ package rater
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Zetkolink/oracle/models/evaluations"
@@ -9,25 +10,35 @@ import (
 	"github.com/Zetkolink/oracle/models/goals"
 	"github.com/Zetkolink/oracle/models/userGoals"
 	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/pseudonym"
+)
+
+var (
+	// ErrNotQueued user goal is not in user rate queue.
+	ErrNotQueued = errors.New("user goal not queued for rate")
 )
 
 type Rater struct {
-	models ModelsSet
+	models    ModelsSet
+	pseudonym *pseudonym.Pseudonym
 }
 
 type Config struct {
-	Models ModelsSet
+	Models    ModelsSet
+	Pseudonym *pseudonym.Pseudonym
 }
 
 type ModelsSet struct {
-	Goals       *goals.Model
-	UserGoals   *userGoals.Model
-	Evaluations *evaluations.Model
-	ForRate     *forRate.Model
+	Goals     goals.Repository
+	UserGoals userGoals.Repository
+	ForRate   forRate.Repository
 }
 
 func NewRater(config Config) *Rater {
-	return &Rater{models: config.Models}
+	return &Rater{
+		models:    config.Models,
+		pseudonym: config.Pseudonym,
+	}
 }
 
 func (r *Rater) GetToRate(ctx context.Context, user *users.User) (*userGoals.UserGoal, string, error) {
@@ -59,24 +70,34 @@ func (r *Rater) GetToRate(ctx context.Context, user *users.User) (*userGoals.Use
 		return nil, "", err
 	}
 
-	message := fmt.Sprintf("Пользователь\n 🙍‍♂ - %d\nДата\n ⏱ - %s\nЗадача\n 💡 - %s",
-		uGoal.UserID+user.ID, uDate.Format("January 2"), goal.Description)
+	alias, err := r.pseudonym.Alias(ctx, user.ID, uGoal.UserID)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	message := fmt.Sprintf("Пользователь\n 🙍‍♂ - %s\nДата\n ⏱ - %s\nЗадача\n 💡 - %s",
+		alias, uDate.Format("January 2"), goal.Description)
 
 	return uGoal, message, nil
 }
 
+// Rate evaluate user goal and remove it from user rate queue.
+// Goal is scored by judge verdict once quorum is reached.
 func (r *Rater) Rate(ctx context.Context, user int64, uGoal int64, eval bool) error {
-	ev := &evaluations.Evaluation{
+	ok, err := r.models.ForRate.Rate(ctx, &evaluations.Evaluation{
 		UserID:     user,
 		UserGoalID: uGoal,
 		Evaluation: eval,
-	}
-
-	err := r.models.Evaluations.Create(ctx, ev)
+	})
 
 	if err != nil {
 		return err
 	}
 
+	if !ok {
+		return ErrNotQueued
+	}
+
 	return nil
 }
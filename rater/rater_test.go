@@ -0,0 +1,78 @@
+package rater
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/forRate"
+)
+
+func TestRate(t *testing.T) {
+	evalModel := evaluations.NewMemory()
+	queue := forRate.NewMemory(evalModel, &forRate.ForRate{
+		UserID:     1,
+		UserGoalID: 10,
+	})
+
+	r := NewRater(Config{
+		Models: ModelsSet{
+			ForRate: queue,
+		},
+	})
+
+	ctx := context.Background()
+
+	err := r.Rate(ctx, 1, 10, true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evals, err := evalModel.ListByUserGoal(ctx, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(evals) != 1 || evals[0].UserID != 1 || !evals[0].Evaluation {
+		t.Fatalf("evaluations = %+v, want one approval of user 1", evals)
+	}
+
+	fRate, err := queue.Get(ctx, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fRate != nil {
+		t.Errorf("user goal %d is still queued", fRate.UserGoalID)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		user  int64
+		uGoal int64
+	}{
+		{name: "rated again", user: 1, uGoal: 10},
+		{name: "not queued", user: 2, uGoal: 10},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Rate(ctx, tt.user, tt.uGoal, false)
+
+			if err != ErrNotQueued {
+				t.Fatalf("err = %v, want %v", err, ErrNotQueued)
+			}
+
+			count, err := evalModel.Count(ctx, 10)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != 1 {
+				t.Errorf("count = %d, want 1", count)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const everyPrefix = "@every "
+
+var (
+	// ErrSpec invalid schedule spec.
+	ErrSpec = errors.New("invalid schedule spec")
+)
+
+// Schedule job schedule.
+type Schedule interface {
+	// Next get next run time after t.
+	Next(t time.Time) time.Time
+
+	// String get schedule spec.
+	String() string
+}
+
+// Parse parse cron expression with minute, hour, day of month,
+// month and day of week fields, or "@every <duration>" interval.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, everyPrefix) {
+		interval, err := time.ParseDuration(strings.TrimPrefix(spec, everyPrefix))
+
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrSpec, spec)
+		}
+
+		return Every(interval), nil
+	}
+
+	return parseCron(spec)
+}
+
+// Every get schedule running with fixed interval.
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+func (e every) String() string {
+	return everyPrefix + time.Duration(e).String()
+}
+
+// cron schedule by cron expression, fields are sets of allowed values.
+type cron struct {
+	spec   string
+	minute map[int]bool
+	hour   map[int]bool
+	dom    map[int]bool
+	month  map[int]bool
+	dow    map[int]bool
+	anyDom bool
+	anyDow bool
+}
+
+// cronField allowed range of cron field.
+type cronField struct {
+	min int
+	max int
+}
+
+var cronFields = []cronField{
+	{min: 0, max: 59},
+	{min: 0, max: 23},
+	{min: 1, max: 31},
+	{min: 1, max: 12},
+	{min: 0, max: 7},
+}
+
+func parseCron(spec string) (*cron, error) {
+	parts := strings.Fields(spec)
+
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%w: %q", ErrSpec, spec)
+	}
+
+	sets := make([]map[int]bool, len(parts))
+
+	for i, part := range parts {
+		set, err := parseField(part, cronFields[i])
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrSpec, spec)
+		}
+
+		sets[i] = set
+	}
+
+	// 7 is sunday too.
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	c := &cron{
+		spec:   spec,
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDom: parts[2] == "*",
+		anyDow: parts[4] == "*",
+	}
+
+	if !c.possible() {
+		return nil, fmt.Errorf("%w: %q never matches", ErrSpec, spec)
+	}
+
+	return c, nil
+}
+
+// monthDays max days in month, february counts leap years.
+var monthDays = [...]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// possible check expression matches some date. Only day of month
+// restricted by day of week "*" may miss every month, like "0 0 31 2 *".
+func (c *cron) possible() bool {
+	if !c.anyDow {
+		return true
+	}
+
+	for month := range c.month {
+		for day := range c.dom {
+			if day <= monthDays[month] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseField parse comma separated list of "*", values,
+// ranges "a-b" with optional step "/n".
+func parseField(field string, bounds cronField) (map[int]bool, error) {
+	set := make(map[int]bool)
+
+	for _, item := range strings.Split(field, ",") {
+		step := 1
+		rangePart := item
+
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+
+			if err != nil || s <= 0 {
+				return nil, ErrSpec
+			}
+
+			step = s
+			rangePart = item[:i]
+		}
+
+		from, to := bounds.min, bounds.max
+
+		if rangePart != "*" {
+			bound := strings.SplitN(rangePart, "-", 2)
+			f, err := strconv.Atoi(bound[0])
+
+			if err != nil {
+				return nil, ErrSpec
+			}
+
+			from, to = f, f
+
+			if len(bound) == 2 {
+				to, err = strconv.Atoi(bound[1])
+
+				if err != nil {
+					return nil, ErrSpec
+				}
+			} else if step > 1 {
+				to = bounds.max
+			}
+		}
+
+		if from < bounds.min || to > bounds.max || from > to {
+			return nil, ErrSpec
+		}
+
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+// Next get next minute after t matching expression. Day matches
+// when either day of month or day of week matches if both are set.
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Parse rejects expressions which never match,
+	// limit only guards against endless search.
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.day(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return limit
+}
+
+func (c *cron) String() string {
+	return c.spec
+}
+
+func (c *cron) day(t time.Time) bool {
+	dom := c.dom[t.Day()]
+	dow := c.dow[int(t.Weekday())]
+
+	if c.anyDom || c.anyDow {
+		return dom && dow
+	}
+
+	return dom || dow
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	// 2026-01-01 is thursday.
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{name: "every minute", spec: "* * * * *",
+			from: date(2026, 1, 1, 10, 7), want: date(2026, 1, 1, 10, 8)},
+		{name: "seconds are dropped", spec: "* * * * *",
+			from: date(2026, 1, 1, 10, 7).Add(30 * time.Second), want: date(2026, 1, 1, 10, 8)},
+		{name: "step", spec: "*/15 * * * *",
+			from: date(2026, 1, 1, 10, 7), want: date(2026, 1, 1, 10, 15)},
+		{name: "step wraps hour", spec: "*/15 * * * *",
+			from: date(2026, 1, 1, 10, 45), want: date(2026, 1, 1, 11, 0)},
+		{name: "step from value", spec: "5/20 * * * *",
+			from: date(2026, 1, 1, 10, 30), want: date(2026, 1, 1, 10, 45)},
+		{name: "range with step", spec: "0 9-17/4 * * *",
+			from: date(2026, 1, 1, 10, 0), want: date(2026, 1, 1, 13, 0)},
+		{name: "range with step wraps day", spec: "0 9-17/4 * * *",
+			from: date(2026, 1, 1, 17, 0), want: date(2026, 1, 2, 9, 0)},
+		{name: "list", spec: "30 8,20 * * *",
+			from: date(2026, 1, 1, 8, 30), want: date(2026, 1, 1, 20, 30)},
+		{name: "month list", spec: "0 0 1 1,7 *",
+			from: date(2026, 2, 1, 0, 0), want: date(2026, 7, 1, 0, 0)},
+		{name: "day of month", spec: "0 0 1 * *",
+			from: date(2026, 1, 15, 0, 0), want: date(2026, 2, 1, 0, 0)},
+		{name: "day of month with any weekday", spec: "0 0 13 * *",
+			from: date(2026, 1, 10, 0, 0), want: date(2026, 1, 13, 0, 0)},
+		{name: "weekday with any day of month", spec: "0 12 * * 1",
+			from: date(2026, 1, 1, 0, 0), want: date(2026, 1, 5, 12, 0)},
+		{name: "day of month or weekday, weekday first", spec: "0 0 13 * 5",
+			from: date(2026, 1, 1, 0, 0), want: date(2026, 1, 2, 0, 0)},
+		{name: "day of month or weekday, day first", spec: "0 0 13 * 5",
+			from: date(2026, 1, 10, 0, 0), want: date(2026, 1, 13, 0, 0)},
+		{name: "sunday as 0", spec: "0 0 * * 0",
+			from: date(2026, 1, 1, 0, 0), want: date(2026, 1, 4, 0, 0)},
+		{name: "sunday as 7", spec: "0 0 * * 7",
+			from: date(2026, 1, 1, 0, 0), want: date(2026, 1, 4, 0, 0)},
+		{name: "weekday range to 7", spec: "0 0 * * 6-7",
+			from: date(2026, 1, 4, 12, 0), want: date(2026, 1, 10, 0, 0)},
+		{name: "leap day", spec: "0 0 29 2 *",
+			from: date(2026, 3, 1, 0, 0), want: date(2028, 2, 29, 0, 0)},
+		{name: "every interval", spec: "@every 5m",
+			from: date(2026, 1, 1, 10, 7), want: date(2026, 1, 1, 10, 12)},
+		{name: "every interval keeps seconds", spec: "@every 90s",
+			from: date(2026, 1, 1, 10, 7).Add(10 * time.Second),
+			want: date(2026, 1, 1, 10, 8).Add(40 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := schedule.Next(tt.from)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "0 * * * *", want: "0 * * * *"},
+		{spec: "  0 * * * *  ", want: "0 * * * *"},
+		{spec: "@every 5m", want: "@every 5m0s"},
+		{spec: "0 0 31 2 5", want: "0 0 31 2 5"},
+	}
+
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+
+		if schedule.String() != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.spec, schedule.String(), tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/-1 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+		"1,,2 * * * *",
+		"@every",
+		"@every 0s",
+		"@every -1m",
+		"@every soon",
+		// Never matching dates.
+		"0 0 31 2 *",
+		"0 0 30 2 *",
+		"0 0 31 4,6,9,11 *",
+	}
+
+	for _, spec := range specs {
+		_, err := Parse(spec)
+
+		if !errors.Is(err, ErrSpec) {
+			t.Errorf("Parse(%q) err = %v, want %v", spec, err, ErrSpec)
+		}
+	}
+}
+
+// Next must stop searching for expressions which never match.
+func TestNextImpossible(t *testing.T) {
+	c := &cron{
+		minute: map[int]bool{0: true},
+		hour:   map[int]bool{0: true},
+		dom:    map[int]bool{31: true},
+		month:  map[int]bool{2: true},
+		dow:    map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true, 5: true, 6: true},
+		anyDow: true,
+	}
+
+	done := make(chan time.Time)
+	from := date(2026, 1, 1, 0, 0)
+
+	go func() {
+		done <- c.Next(from)
+	}()
+
+	select {
+	case next := <-done:
+		if next.Before(from.AddDate(5, 0, 0)) {
+			t.Errorf("Next = %s, want no match within 5 years", next)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Next does not stop")
+	}
+}
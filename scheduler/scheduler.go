@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// historySize runs kept in job history.
+const historySize = 20
+
+var (
+	// ErrExists job with same name registered.
+	ErrExists = errors.New("job exists")
+)
+
+// Scheduler run registered jobs by their schedules. Runs of one
+// job never overlap, run times missed while job was running
+// are skipped.
+type Scheduler struct {
+	mu     sync.RWMutex
+	jobs   map[string]*job
+	random *rand.Rand
+	wg     sync.WaitGroup
+}
+
+// Job scheduled job.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Jitter max random delay added to every run,
+	// so instances don't hit storage at same time.
+	Jitter time.Duration
+	// Immediate run job once on start.
+	Immediate bool
+	Run       func(ctx context.Context) error
+}
+
+// Status job state and run history.
+type Status struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	Running   bool       `json:"running"`
+	NextRun   *time.Time `json:"next_run"`
+	LastRun   *Run       `json:"last_run"`
+	LastError string     `json:"last_error"`
+	History   []Run      `json:"history"`
+}
+
+// Run job run result.
+type Run struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error"`
+}
+
+type job struct {
+	Job
+	running   bool
+	nextRun   *time.Time
+	lastError string
+	history   []Run
+}
+
+// NewScheduler create new instance of Scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		jobs:   make(map[string]*job),
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Register add job, should be called before Start.
+func (s *Scheduler) Register(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[j.Name]; ok {
+		return ErrExists
+	}
+
+	s.jobs[j.Name] = &job{Job: j}
+
+	return nil
+}
+
+// Start run jobs until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+
+		go func(j *job) {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+}
+
+// Wait wait running jobs to finish.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Statuses get jobs states sorted by name.
+func (s *Scheduler) Statuses() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+
+	for _, j := range s.jobs {
+		status := Status{
+			Name:      j.Name,
+			Schedule:  j.Schedule.String(),
+			Running:   j.running,
+			NextRun:   j.nextRun,
+			LastError: j.lastError,
+			History:   append([]Run(nil), j.history...),
+		}
+
+		if len(j.history) > 0 {
+			last := j.history[len(j.history)-1]
+			status.LastRun = &last
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	if j.Immediate {
+		s.run(ctx, j)
+	}
+
+	for {
+		next := j.Schedule.Next(time.Now()).Add(s.jitter(j.Jitter))
+		s.setNext(j, &next)
+
+		select {
+		case <-ctx.Done():
+			s.setNext(j, nil)
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		s.run(ctx, j)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	s.mu.Lock()
+	j.running = true
+	j.nextRun = nil
+	s.mu.Unlock()
+
+	run := Run{StartedAt: time.Now()}
+	err := j.Run(ctx)
+	run.FinishedAt = time.Now()
+
+	if err != nil {
+		run.Error = err.Error()
+		log.Printf("job %s: %v", j.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.running = false
+	j.lastError = run.Error
+	j.history = append(j.history, run)
+
+	if len(j.history) > historySize {
+		j.history = j.history[len(j.history)-historySize:]
+	}
+}
+
+func (s *Scheduler) setNext(j *job, next *time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.nextRun = next
+}
+
+func (s *Scheduler) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Duration(s.random.Int63n(int64(max)))
+}
@@ -0,0 +1,149 @@
+package scorer
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+type Scorer struct {
+	models ModelsSet
+}
+
+type Config struct {
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	Users       users.Repository
+	GoalTypes   goalTypes.Repository
+	Evaluations evaluations.Repository
+	Scores      scores.Repository
+}
+
+// Summary user scores for periods containing date.
+type Summary struct {
+	Day   int64
+	Week  int64
+	Month int64
+}
+
+func NewScorer(config Config) *Scorer {
+	return &Scorer{models: config.Models}
+}
+
+// Score compute user goal points and store them.
+// Safe to call several times, points are recomputed.
+// Goals of deleted users are skipped.
+func (s *Scorer) Score(ctx context.Context, uGoal *userGoals.UserGoal) error {
+	user, err := s.models.Users.Get(ctx, uGoal.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	points, err := s.Points(ctx, uGoal)
+
+	if err != nil {
+		return err
+	}
+
+	uDate, err := user.Date(uGoal.From)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.models.Scores.Set(ctx, &scores.Score{
+		UserGoalID: uGoal.ID,
+		UserID:     uGoal.UserID,
+		Date:       scores.PeriodStart(scores.PeriodDay, *uDate),
+		Points:     points,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Points get points earned by user goal.
+// Only finished complete goals earn points, evaluated goals
+// earn them while peers approve.
+func (s *Scorer) Points(ctx context.Context, uGoal *userGoals.UserGoal) (int64, error) {
+	if uGoal.Phase != userGoals.PhaseFinished ||
+		uGoal.Status != userGoals.StatusComplete {
+
+		return 0, nil
+	}
+
+	gType, err := s.models.GoalTypes.Get(ctx, uGoal.Type)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !gType.Evaluated {
+		return gType.Points, nil
+	}
+
+	approved, err := s.models.Evaluations.GetResult(ctx, uGoal.ID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !approved {
+		return 0, nil
+	}
+
+	return gType.Points, nil
+}
+
+// Summary get user scores for day, week and month containing date.
+func (s *Scorer) Summary(ctx context.Context, user *users.User, date time.Time) (*Summary, error) {
+	uDate, err := user.Date(date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var summary Summary
+
+	for period, points := range map[string]*int64{
+		scores.PeriodDay:   &summary.Day,
+		scores.PeriodWeek:  &summary.Week,
+		scores.PeriodMonth: &summary.Month,
+	} {
+		*points, err = s.models.Scores.Get(ctx, user.ID, period, *uDate)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &summary, nil
+}
+
+// Leaderboard get best users for period containing date.
+func (s *Scorer) Leaderboard(ctx context.Context, user *users.User, period string,
+	date time.Time, limit int) ([]*scores.Entry, error) {
+
+	uDate, err := user.Date(date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.models.Scores.Leaderboard(ctx, period, *uDate, limit)
+}
@@ -0,0 +1,88 @@
+package scorer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/evaluations"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+func newScorer(t *testing.T) (*Scorer, *scores.Memory) {
+	usersModel := users.NewMemory()
+
+	err := usersModel.Create(context.Background(), &users.User{
+		ID:       1,
+		Timezone: "UTC",
+		DayStart: users.DefaultDayStart,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scoresModel := scores.NewMemory()
+
+	s := NewScorer(Config{
+		Models: ModelsSet{
+			Users: usersModel,
+			GoalTypes: goalTypes.NewMemory(&goalTypes.GoalType{
+				ID:     1,
+				Points: 10,
+			}),
+			Evaluations: evaluations.NewMemory(),
+			Scores:      scoresModel,
+		},
+	})
+
+	return s, scoresModel
+}
+
+func TestScore(t *testing.T) {
+	from := time.Date(2026, time.March, 2, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		user   int64
+		status string
+		points int64
+	}{
+		{name: "complete", user: 1, status: userGoals.StatusComplete, points: 10},
+		{name: "failed", user: 1, status: userGoals.StatusFailed, points: 0},
+		{name: "missing user", user: 2, status: userGoals.StatusComplete, points: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, scoresModel := newScorer(t)
+
+			err := s.Score(context.Background(), &userGoals.UserGoal{
+				ID:     1,
+				UserID: tt.user,
+				Type:   1,
+				Phase:  userGoals.PhaseFinished,
+				Status: tt.status,
+				From:   from,
+				To:     from.Add(24 * time.Hour),
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			points, err := scoresModel.Get(context.Background(), tt.user, scores.PeriodDay, from)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if points != tt.points {
+				t.Errorf("points = %d, want %d", points, tt.points)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/account"
+	"github.com/Zetkolink/oracle/services/appeal"
+	"github.com/Zetkolink/oracle/services/appraiser"
+	"github.com/Zetkolink/oracle/services/menu"
+	"github.com/Zetkolink/oracle/services/rating"
+	"github.com/Zetkolink/oracle/services/registrar"
+	"github.com/Zetkolink/oracle/services/router"
+	"github.com/Zetkolink/oracle/services/settings"
+	"github.com/Zetkolink/oracle/services/stats"
+	"github.com/Zetkolink/oracle/services/tasks"
+)
+
+// registerScreens register screen handlers which talk to user
+// through service.
+func (o *oracle) registerScreens(r *router.Router, service services.Service) {
+	r.Register(services.StateRegister, registrar.NewRegistrar(registrar.Config{
+		Sender:   service,
+		Profiler: service,
+		Locator:  o.locator,
+		Models: registrar.ModelsSet{
+			WhiteList: o.models.whiteList,
+			Users:     o.models.users,
+			Keyboards: o.models.keyboards,
+		},
+	}))
+
+	r.Register(services.StateMenu, menu.NewMenu(menu.Config{
+		Sender: service,
+		Models: menu.ModelsSet{
+			WhiteList: o.models.whiteList,
+			Users:     o.models.users,
+			Keyboards: o.models.keyboards,
+		},
+	}))
+
+	r.Register(services.StateTasks, tasks.NewTasks(tasks.Config{
+		Sender: service,
+		Models: tasks.ModelsSet{
+			Users:     o.models.users,
+			Keyboards: o.models.keyboards,
+			GoalTypes: o.models.goalTypes,
+			Goals:     o.models.goals,
+			Proofs:    o.models.proofs,
+			Templates: o.models.templates,
+			Streaks:   o.models.streaks,
+		},
+		Manager:     o.manager,
+		RedisClient: o.redisClient,
+	}))
+
+	r.Register(services.StateRate, appraiser.NewAppraiser(appraiser.Config{
+		Sender: service,
+		Rater:  o.rater,
+		Judge:  o.judge,
+		Models: appraiser.ModelsSet{
+			Users:  o.models.users,
+			Proofs: o.models.proofs,
+		},
+	}))
+
+	r.Register(services.StateRating, rating.NewRating(rating.Config{
+		Sender:    service,
+		Scorer:    o.scorer,
+		Pseudonym: o.pseudonym,
+		Models: rating.ModelsSet{
+			Users: o.models.users,
+		},
+	}))
+
+	r.Register(services.StateAppeal, appeal.NewAppeal(appeal.Config{
+		Sender:      service,
+		Judge:       o.judge,
+		RedisClient: o.redisClient,
+		Models: appeal.ModelsSet{
+			Users: o.models.users,
+		},
+	}))
+
+	r.Register(services.StateStats, stats.NewStats(stats.Config{
+		Sender:     service,
+		Statistics: o.statistics,
+		Exporter:   o.exporter,
+		Models: stats.ModelsSet{
+			Users:     o.models.users,
+			GoalTypes: o.models.goalTypes,
+		},
+	}))
+
+	r.Register(services.StateAccount, account.NewAccount(account.Config{
+		Sender: service,
+		Eraser: o.eraser,
+		Models: account.ModelsSet{
+			Users: o.models.users,
+		},
+	}))
+
+	r.Register(services.StateSettings, settings.NewSettings(settings.Config{
+		Sender:      service,
+		Locator:     o.locator,
+		RedisClient: o.redisClient,
+		Notificator: o.notificator,
+		Models: settings.ModelsSet{
+			Users: o.models.users,
+		},
+	}))
+}
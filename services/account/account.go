@@ -0,0 +1,130 @@
+package account
+
+import (
+	"context"
+
+	"github.com/Zetkolink/oracle/eraser"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+)
+
+var (
+	confirmBtn = &keyboard.Button{
+		Color: "negative",
+		Action: keyboard.Action{
+			Label: "Удалить навсегда",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "confirm_delete",
+			},
+		},
+	}
+
+	cancelBtn = &keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Отмена",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "settings",
+			},
+		},
+	}
+)
+
+// Account account deletion screen, it is opened from settings.
+type Account struct {
+	sender services.Sender
+	eraser *eraser.Eraser
+	models ModelsSet
+}
+
+type Config struct {
+	Sender services.Sender
+	Eraser *eraser.Eraser
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	Users users.Repository
+}
+
+func NewAccount(config Config) *Account {
+	return &Account{
+		sender: config.Sender,
+		eraser: config.Eraser,
+		models: config.Models,
+	}
+}
+
+func (a *Account) Handle(ctx context.Context, message services.Message) (string, error) {
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	if payload != nil {
+		switch payload.GetCommand() {
+		case "settings":
+			err = a.models.Users.UpdateState(ctx, message.GetPeer(), services.StateSettings)
+
+			if err != nil {
+				return "", err
+			}
+
+			return services.StateSettings, nil
+		case "confirm_delete":
+			err = a.eraser.Erase(ctx, message.GetPeer())
+
+			if err != nil {
+				return "", err
+			}
+
+			err = a.sender.SendMessage(message.GetPeer(),
+				"Аккаунт и все ваши данные удалены. Напишите, если захотите вернуться")
+
+			if err != nil {
+				return "", err
+			}
+
+			return "", nil
+		}
+	}
+
+	err = a.SendMain(message.GetPeer())
+
+	if err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+// SendMain ask user to confirm account deletion.
+func (a *Account) SendMain(peerID int64) error {
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  1,
+	})
+
+	kb.SetButton(0, 0, confirmBtn)
+	kb.SetFooter(cancelBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = a.sender.SendKeyboard(peerID, "Удалить аккаунт?\n"+
+		"Задачи, оценки, серии и очки будут удалены без возможности восстановления", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
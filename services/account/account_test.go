@@ -0,0 +1,75 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/services/recorder"
+)
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload *keyboard.Payload
+		next    string
+		state   string
+		sent    int
+	}{
+		{name: "ask", next: "", state: services.StateAccount, sent: 1},
+		{name: "cancel", payload: &keyboard.Payload{Command: "settings"},
+			next: services.StateSettings, state: services.StateSettings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recorder.NewRecorder()
+			usersModel := users.NewMemory()
+
+			err := usersModel.Create(context.Background(), &users.User{
+				ID:    1,
+				State: services.StateAccount,
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a := NewAccount(Config{
+				Sender: rec,
+				Models: ModelsSet{
+					Users: usersModel,
+				},
+			})
+
+			next, err := a.Handle(context.Background(), &recorder.Message{
+				Peer:    1,
+				Payload: tt.payload,
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if next != tt.next {
+				t.Errorf("next = %q, want %q", next, tt.next)
+			}
+
+			user, err := usersModel.Get(context.Background(), 1)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.State != tt.state {
+				t.Errorf("state = %q, want %q", user.State, tt.state)
+			}
+
+			if len(rec.Records()) != tt.sent {
+				t.Errorf("sent %d messages, want %d", len(rec.Records()), tt.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package appeal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Zetkolink/oracle/judge"
+	"github.com/Zetkolink/oracle/models/proofs"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/state"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	appeal = "appeal"
+)
+
+var (
+	cancelBtn = &keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Меню",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "menu",
+			},
+		},
+	}
+)
+
+type Appeal struct {
+	sender      services.Sender
+	judge       *judge.Judge
+	redisClient *redis.Client
+	models      ModelsSet
+}
+
+type Config struct {
+	Sender      services.Sender
+	Judge       *judge.Judge
+	RedisClient *redis.Client
+	Models      ModelsSet
+}
+
+type ModelsSet struct {
+	Users users.Repository
+}
+
+func NewAppeal(config Config) *Appeal {
+	return &Appeal{
+		sender:      config.Sender,
+		judge:       config.Judge,
+		redisClient: config.RedisClient,
+		models:      config.Models,
+	}
+}
+
+func (a *Appeal) Handle(ctx context.Context, message services.Message) (string, error) {
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	st, err := state.NewState(ctx, message.GetPeer(), appeal, a.redisClient)
+
+	if err != nil {
+		return "", err
+	}
+
+	if payload != nil {
+		switch payload.GetCommand() {
+		case "appeal":
+			uGoal, ok := payload.GetParam("user_goal").(float64)
+
+			if !ok {
+				return a.toMenu(ctx, message.GetPeer(), st)
+			}
+
+			err = st.SetParams(ctx, int64(uGoal))
+
+			if err != nil {
+				return "", err
+			}
+
+			err = a.SendMain(message.GetPeer())
+
+			if err != nil {
+				return "", err
+			}
+
+			return "", nil
+		default:
+			return a.toMenu(ctx, message.GetPeer(), st)
+		}
+	}
+
+	uGoal, ok := st.Params.(float64)
+
+	if !ok {
+		return a.toMenu(ctx, message.GetPeer(), st)
+	}
+
+	text := strings.TrimSpace(message.GetText())
+	attachments := message.GetAttachments()
+
+	if text == "" && len(attachments) == 0 {
+		err = a.SendMain(message.GetPeer())
+
+		if err != nil {
+			return "", err
+		}
+
+		return "", nil
+	}
+
+	proof := &proofs.Proof{
+		Text: text,
+	}
+
+	for _, attachment := range attachments {
+		proof.Attachments = append(proof.Attachments, &proofs.Attachment{
+			Service: attachment.Service,
+			Type:    attachment.Type,
+			ID:      attachment.ID,
+		})
+	}
+
+	reply := "Обжалование отправлено на повторную проверку"
+	err = a.judge.Appeal(ctx, message.GetPeer(), int64(uGoal), proof)
+
+	if err == judge.ErrNoAppeal {
+		reply = "Это решение уже нельзя обжаловать"
+	} else if err != nil {
+		return "", err
+	}
+
+	err = a.sender.SendMessage(message.GetPeer(), reply)
+
+	if err != nil {
+		return "", err
+	}
+
+	return a.toMenu(ctx, message.GetPeer(), st)
+}
+
+// SendMain ask user for proof.
+func (a *Appeal) SendMain(peerID int64) error {
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  0,
+	})
+
+	kb.SetFooter(cancelBtn)
+
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = a.sender.SendKeyboard(peerID,
+		"Отправьте фото или текстовое описание выполнения задачи", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *Appeal) toMenu(ctx context.Context, peerID int64, st *state.State) (string, error) {
+	st.Clear(ctx)
+
+	err := a.models.Users.UpdateState(ctx, peerID, services.StateMenu)
+
+	if err != nil {
+		return "", err
+	}
+
+	return services.StateMenu, nil
+}
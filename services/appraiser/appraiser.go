@@ -6,15 +6,12 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/Zetkolink/oracle/models/goals"
-	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/judge"
+	"github.com/Zetkolink/oracle/models/proofs"
 	"github.com/Zetkolink/oracle/models/users"
-	"github.com/Zetkolink/oracle/notificator"
 	"github.com/Zetkolink/oracle/rater"
 	"github.com/Zetkolink/oracle/services"
-	"github.com/Zetkolink/oracle/services/vk/keyboard"
-	"github.com/go-redis/redis/v8"
-	"github.com/go-vk-api/vk"
+	"github.com/Zetkolink/oracle/services/keyboard"
 )
 
 var (
@@ -31,32 +28,30 @@ var (
 )
 
 type Appraiser struct {
-	vkClient    *vk.Client
-	rater       *rater.Rater
-	redisClient *redis.Client
-	models      ModelsSet
-	notificator *notificator.Notificator
+	sender services.Sender
+	rater  *rater.Rater
+	judge  *judge.Judge
+	models ModelsSet
 }
 
 type Config struct {
-	VKClient    *vk.Client
-	Rater       *rater.Rater
-	Models      ModelsSet
-	Notificator *notificator.Notificator
+	Sender services.Sender
+	Rater  *rater.Rater
+	Judge  *judge.Judge
+	Models ModelsSet
 }
 
 type ModelsSet struct {
-	Users     *users.Model
-	UserGoals *userGoals.Model
-	Goals     *goals.Model
+	Users  users.Repository
+	Proofs proofs.Repository
 }
 
 func NewAppraiser(config Config) *Appraiser {
 	return &Appraiser{
-		vkClient:    config.VKClient,
-		rater:       config.Rater,
-		models:      config.Models,
-		notificator: config.Notificator,
+		sender: config.Sender,
+		rater:  config.Rater,
+		judge:  config.Judge,
+		models: config.Models,
 	}
 }
 
@@ -79,13 +74,13 @@ func (r *Appraiser) Handle(ctx context.Context, message services.Message) (strin
 
 	switch payload.GetCommand() {
 	case "menu":
-		err = r.models.Users.UpdateState(ctx, message.GetPeer(), "menu")
+		err = r.models.Users.UpdateState(ctx, message.GetPeer(), services.StateMenu)
 
 		if err != nil {
 			return "", err
 		}
 
-		return "menu", nil
+		return services.StateMenu, nil
 	case "approve", "disapprove":
 		uGoalParam, ok := payload.GetParam("user_goal").(float64)
 
@@ -103,18 +98,16 @@ func (r *Appraiser) Handle(ctx context.Context, message services.Message) (strin
 
 		err = r.rater.Rate(ctx, message.GetPeer(), uGoalID, eval)
 
-		if err != nil {
+		if err != nil && err != rater.ErrNotQueued {
 			return "", err
 		}
 
-		if !eval {
-			go func() {
-				err := r.Notify(ctx, uGoalID)
+		if err == nil {
+			err := r.judge.Check(ctx, uGoalID)
 
-				if err != nil {
-					log.Println(err)
-				}
-			}()
+			if err != nil {
+				log.Println(err)
+			}
 		}
 
 		err := r.SendMain(ctx, message.GetUser())
@@ -158,12 +151,7 @@ func (r *Appraiser) SendMain(ctx context.Context, user *users.User) error {
 			return err
 		}
 
-		err = r.vkClient.CallMethod("messages.send", vk.RequestParams{
-			"peer_id":   user.ID,
-			"message":   "На данный момент вы оценили все",
-			"random_id": 0,
-			"keyboard":  kbStr,
-		}, nil)
+		err = r.sender.SendKeyboard(user.ID, "На данный момент вы оценили все", kbStr)
 
 		if err != nil {
 			return err
@@ -210,52 +198,57 @@ func (r *Appraiser) SendMain(ctx context.Context, user *users.User) error {
 		return err
 	}
 
-	err = r.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   goal,
-		"random_id": 0,
-		"keyboard":  kbStr,
-	}, nil)
+	goal, err = r.sendProofs(ctx, user, uGoal.ID, goal)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-func (r *Appraiser) Notify(ctx context.Context, uGoalID int64) error {
-	userGoal, err := r.models.UserGoals.Get(ctx, uGoalID)
+	err = r.sender.SendKeyboard(user.ID, goal, kbStr)
 
 	if err != nil {
 		return err
 	}
 
-	goal, err := r.models.Goals.Get(ctx, userGoal.GoalID)
+	return nil
+}
 
-	if err != nil {
-		return err
-	}
+// sendProofs send proof attachments of user goal and
+// return goal message with proof descriptions.
+func (r *Appraiser) sendProofs(ctx context.Context, user *users.User,
+	uGoalID int64, goal string) (string, error) {
 
-	user, err := r.models.Users.Get(ctx, userGoal.UserID)
+	list, err := r.models.Proofs.ListByUserGoal(ctx, uGoalID)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	uDate, err := user.Date(userGoal.From)
+	var attachments []services.Attachment
 
-	if err != nil {
-		return err
+	for _, proof := range list {
+		if proof.Text != "" {
+			goal += fmt.Sprintf("\nПодтверждение\n 📎 - %s", proof.Text)
+		}
+
+		for _, attachment := range proof.Attachments {
+			attachments = append(attachments, services.Attachment{
+				Service: attachment.Service,
+				Type:    attachment.Type,
+				ID:      attachment.ID,
+			})
+		}
 	}
 
-	err = r.notificator.Send(user, "disapprove",
-		fmt.Sprintf("Ваша задача была помечена как невалидная\nДата\n ⏱ - %s\nЗадача\n 💡 - %s",
-			uDate.Format("January 2"), goal.Description))
+	if len(attachments) == 0 {
+		return goal, nil
+	}
+
+	err = r.sender.SendAttachments(user.ID, "Приложенные подтверждения", attachments)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return goal, nil
 }
@@ -0,0 +1,258 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/router"
+)
+
+var (
+	errNoGoalLister = errors.New("goal list screen not registered")
+	errNoReporter   = errors.New("statistics screen not registered")
+)
+
+// Delivery renders and sends notifications to users of one transport.
+type Delivery struct {
+	transport string
+	sender    services.Sender
+	router    *router.Router
+	pool      *pool.Pool
+	models    ModelsSet
+}
+
+// Config configuration for Delivery.
+type Config struct {
+	// Transport name of transport which users are served.
+	Transport string
+	Sender    services.Sender
+	// Router screens router of transport.
+	Router *router.Router
+	// Pool workers pool of transport updates, notifications are
+	// sent in user queue, so they are ordered with user messages.
+	Pool   *pool.Pool
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	Users     users.Repository
+	Keyboards keyboards.Repository
+}
+
+// goalLister screen which renders user goal lists for notifications.
+type goalLister interface {
+	GoalList(ctx context.Context, user *users.User, date time.Time) (string, error)
+	MarkList(ctx context.Context, user *users.User, date time.Time) (string, error)
+}
+
+// reporter screen which renders user statistics for notifications.
+type reporter interface {
+	SendReport(ctx context.Context, user *users.User, title string) error
+}
+
+// NewDelivery create new instance of Delivery.
+func NewDelivery(config Config) *Delivery {
+	return &Delivery{
+		transport: config.Transport,
+		sender:    config.Sender,
+		router:    config.Router,
+		pool:      config.Pool,
+		models:    config.Models,
+	}
+}
+
+// Owns check user is served by delivery transport.
+func (d *Delivery) Owns(userID int64) bool {
+	return services.Transport(userID) == d.transport
+}
+
+// Deliver render and send notification to user in user queue
+// and wait it is sent.
+func (d *Delivery) Deliver(ctx context.Context, message *notificator.Message) error {
+	done := make(chan error, 1)
+
+	err := d.pool.Submit(message.User.ID, func() {
+		done <- d.deliver(ctx, message)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return <-done
+}
+
+// deliver render and send notification to user.
+func (d *Delivery) deliver(ctx context.Context, message *notificator.Message) error {
+	switch message.Code {
+	case "verdict":
+		return d.sendVerdict(ctx, message)
+	case "streak":
+		return d.sender.SendMessage(message.User.ID, message.Text)
+	case "weekly_report":
+		rep, err := d.reporter()
+
+		if err != nil {
+			return err
+		}
+
+		return rep.SendReport(ctx, message.User, "Итоги недели")
+	case "next_day":
+		return d.sender.SendMessage(message.User.ID, "Не забудьте создать список задач на зватрашний день")
+	case "task_list":
+		uDate, err := listDate(message)
+
+		if err != nil {
+			return err
+		}
+
+		lister, err := d.goalLister()
+
+		if err != nil {
+			return err
+		}
+
+		list, err := lister.GoalList(ctx, message.User, *uDate)
+
+		if err != nil {
+			return err
+		}
+
+		return d.sender.SendMessage(message.User.ID, "Доброе утро! Ваш список задач на сегодня\n\n"+list)
+	case "mark_tasks":
+		uDate, err := message.User.Date(time.Now())
+
+		if err != nil {
+			return err
+		}
+
+		lister, err := d.goalLister()
+
+		if err != nil {
+			return err
+		}
+
+		list, err := lister.MarkList(ctx, message.User, *uDate)
+
+		if err != nil {
+			return err
+		}
+
+		kb, err := d.models.Keyboards.GetKeyboard(ctx, d.transport, "tasks")
+
+		if err != nil {
+			return err
+		}
+
+		err = d.models.Users.UpdateState(ctx, message.User.ID, services.StateTasks)
+
+		if err != nil {
+			return err
+		}
+
+		return d.sender.SendKeyboard(message.User.ID, "Доброе вечер! Обновите статусы задач\n\n"+list, kb)
+	}
+
+	return fmt.Errorf("unknown notification code %q", message.Code)
+}
+
+// sendVerdict send review verdict, offer appeal if allowed.
+func (d *Delivery) sendVerdict(ctx context.Context, message *notificator.Message) error {
+	if message.Params["appeal"] != true {
+		return d.sender.SendMessage(message.User.ID, message.Text)
+	}
+
+	err := d.models.Users.UpdateState(ctx, message.User.ID, services.StateAppeal)
+
+	if err != nil {
+		return err
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  1,
+	})
+
+	kb.SetButton(0, 0, &keyboard.Button{
+		Color: "primary",
+		Action: keyboard.Action{
+			Label: "Обжаловать",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "appeal",
+				Params: map[string]interface{}{
+					"user_goal": message.Params["user_goal"],
+				},
+			},
+		},
+	})
+
+	kb.SetFooter(&keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Меню",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "menu",
+			},
+		},
+	})
+
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	return d.sender.SendKeyboard(message.User.ID, message.Text, kbStr)
+}
+
+// listDate get time inside user day of task list, list may
+// be sent before its day starts.
+func listDate(message *notificator.Message) (*time.Time, error) {
+	date, ok := message.Params["date"].(string)
+
+	if !ok {
+		return message.User.Date(time.Now())
+	}
+
+	day, err := time.Parse("2006-01-02", date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return message.User.Midday(day)
+}
+
+func (d *Delivery) goalLister() (goalLister, error) {
+	handler, _ := d.router.Handler(services.StateTasks)
+	lister, ok := handler.(goalLister)
+
+	if !ok {
+		return nil, errNoGoalLister
+	}
+
+	return lister, nil
+}
+
+func (d *Delivery) reporter() (reporter, error) {
+	handler, _ := d.router.Handler(services.StateStats)
+	rep, ok := handler.(reporter)
+
+	if !ok {
+		return nil, errNoReporter
+	}
+
+	return rep, nil
+}
@@ -0,0 +1,223 @@
+package delivery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/recorder"
+	"github.com/Zetkolink/oracle/services/router"
+)
+
+// lister tasks screen stub, which keeps listed date.
+type lister struct {
+	router.HandlerFunc
+	date time.Time
+}
+
+func (l *lister) GoalList(_ context.Context, _ *users.User, date time.Time) (string, error) {
+	l.date = date
+
+	return "goals", nil
+}
+
+func (l *lister) MarkList(context.Context, *users.User, time.Time) (string, error) {
+	return "marks", nil
+}
+
+func newTestDelivery(t *testing.T, transport string, user *users.User) (*Delivery, *recorder.Recorder,
+	*users.Memory, *pool.Pool) {
+
+	d, rec, usersModel, workers, _ := newTestDeliveryLister(t, transport, user)
+
+	return d, rec, usersModel, workers
+}
+
+func newTestDeliveryLister(t *testing.T, transport string, user *users.User) (*Delivery, *recorder.Recorder,
+	*users.Memory, *pool.Pool, *lister) {
+
+	usersModel := users.NewMemory()
+
+	err := usersModel.Create(context.Background(), user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := recorder.NewRecorder()
+	workers := pool.NewPool(pool.Config{Size: 1})
+	t.Cleanup(workers.Stop)
+
+	l := &lister{}
+	r := router.NewRouter()
+	r.Register(services.StateTasks, l)
+
+	d := NewDelivery(Config{
+		Transport: transport,
+		Sender:    rec,
+		Router:    r,
+		Pool:      workers,
+		Models: ModelsSet{
+			Users: usersModel,
+			Keyboards: keyboards.NewMemory(
+				&keyboards.Keyboard{Service: services.TransportVK, Name: "tasks", Keyboard: "vk tasks"},
+				&keyboards.Keyboard{Service: services.TransportTelegram, Name: "tasks", Keyboard: "telegram tasks"},
+			),
+		},
+	})
+
+	return d, rec, usersModel, workers, l
+}
+
+func TestDeliverKeyboardByTransport(t *testing.T) {
+	tests := []struct {
+		transport string
+		userID    int64
+		keyboard  string
+	}{
+		{transport: services.TransportVK, userID: 100, keyboard: "vk tasks"},
+		{transport: services.TransportTelegram, userID: services.TelegramUserID(100),
+			keyboard: "telegram tasks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transport, func(t *testing.T) {
+			user := &users.User{ID: tt.userID, Timezone: "UTC", DayStart: users.DefaultDayStart}
+			d, rec, usersModel, _ := newTestDelivery(t, tt.transport, user)
+			ctx := context.Background()
+
+			err := d.Deliver(ctx, &notificator.Message{User: user, Code: "mark_tasks"})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			records := rec.Records()
+
+			if len(records) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(records))
+			}
+
+			if records[0].Keyboard != tt.keyboard {
+				t.Errorf("keyboard = %q, want %q", records[0].Keyboard, tt.keyboard)
+			}
+
+			got, err := usersModel.Get(ctx, user.ID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.State != services.StateTasks {
+				t.Errorf("state = %q, want %q", got.State, services.StateTasks)
+			}
+		})
+	}
+}
+
+// Notification must wait for user message in flight.
+func TestDeliverInUserQueue(t *testing.T) {
+	user := &users.User{ID: 100, Timezone: "UTC", DayStart: users.DefaultDayStart}
+	d, rec, _, workers := newTestDelivery(t, services.TransportVK, user)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	err := workers.Submit(user.ID, func() {
+		close(started)
+		<-release
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- d.Deliver(context.Background(), &notificator.Message{
+			User: user,
+			Code: "streak",
+			Text: "streak",
+		})
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("delivered while user message is handled, err %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if len(rec.Records()) != 0 {
+		t.Fatal("sent while user message is handled")
+	}
+
+	close(release)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("not delivered")
+	}
+
+	if len(rec.Records()) != 1 {
+		t.Errorf("sent %d messages, want 1", len(rec.Records()))
+	}
+}
+
+func TestDeliverStoppedPool(t *testing.T) {
+	user := &users.User{ID: 100, Timezone: "UTC", DayStart: users.DefaultDayStart}
+	d, rec, _, workers := newTestDelivery(t, services.TransportVK, user)
+
+	workers.Stop()
+
+	err := d.Deliver(context.Background(), &notificator.Message{
+		User: user,
+		Code: "streak",
+		Text: "streak",
+	})
+
+	if err != pool.ErrStopped {
+		t.Errorf("err = %v, want %v", err, pool.ErrStopped)
+	}
+
+	if len(rec.Records()) != 0 {
+		t.Error("sent by stopped pool")
+	}
+}
+
+// Task list may be sent before its day starts, it lists goals of its date.
+func TestDeliverTaskListDate(t *testing.T) {
+	user := &users.User{ID: 100, Timezone: "UTC", DayStart: 10}
+	d, _, _, _, l := newTestDeliveryLister(t, services.TransportVK, user)
+
+	err := d.Deliver(context.Background(), &notificator.Message{
+		User:   user,
+		Code:   "task_list",
+		Params: map[string]interface{}{"date": "2026-05-11"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day, err := user.Day(l.date)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC); !day.Equal(want) {
+		t.Errorf("listed day = %s, want %s", day, want)
+	}
+}
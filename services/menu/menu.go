@@ -0,0 +1,123 @@
+package menu
+
+import (
+	"context"
+
+	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/models/whiteList"
+	"github.com/Zetkolink/oracle/services"
+)
+
+const (
+	menu = "menu"
+)
+
+type Menu struct {
+	sender services.Sender
+	models ModelsSet
+}
+
+type Config struct {
+	Sender services.Sender
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	WhiteList whiteList.Repository
+	Users     users.Repository
+	Keyboards keyboards.Repository
+}
+
+func NewMenu(config Config) *Menu {
+	return &Menu{
+		sender: config.Sender,
+		models: config.Models,
+	}
+}
+
+func (m *Menu) Handle(ctx context.Context, message services.Message) (string, error) {
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	if payload == nil {
+		err := m.SendMain(ctx, message.GetPeer())
+
+		if err != nil {
+			return "", err
+		}
+
+		return "", nil
+	}
+
+	switch payload.GetCommand() {
+	case "to_tasks":
+		err = m.models.Users.UpdateState(ctx, message.GetPeer(), services.StateTasks)
+
+		if err != nil {
+			return "", err
+		}
+
+		return services.StateTasks, nil
+	case "to_rate":
+		err = m.models.Users.UpdateState(ctx, message.GetPeer(), services.StateRate)
+
+		if err != nil {
+			return "", err
+		}
+
+		return services.StateRate, nil
+	case "to_rating":
+		err = m.models.Users.UpdateState(ctx, message.GetPeer(), services.StateRating)
+
+		if err != nil {
+			return "", err
+		}
+
+		return services.StateRating, nil
+	case "to_stats":
+		err = m.models.Users.UpdateState(ctx, message.GetPeer(), services.StateStats)
+
+		if err != nil {
+			return "", err
+		}
+
+		return services.StateStats, nil
+	case "to_settings":
+		err = m.models.Users.UpdateState(ctx, message.GetPeer(), services.StateSettings)
+
+		if err != nil {
+			return "", err
+		}
+
+		return services.StateSettings, nil
+	default:
+		err := m.SendMain(ctx, message.GetPeer())
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+func (m *Menu) SendMain(ctx context.Context, peerID int64) error {
+	kb, err := m.models.Keyboards.GetKeyboard(ctx,
+		services.Transport(peerID), "menu")
+
+	if err != nil {
+		return err
+	}
+
+	err = m.sender.SendKeyboard(peerID, "Главное меню", kb)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
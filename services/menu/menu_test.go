@@ -0,0 +1,135 @@
+package menu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/models/whiteList"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/services/recorder"
+)
+
+const (
+	userID     = 1
+	menuLayout = `{"buttons":[]}`
+)
+
+func newMenu(t *testing.T) (*Menu, *recorder.Recorder, *users.Memory) {
+	rec := recorder.NewRecorder()
+	usersModel := users.NewMemory()
+
+	err := usersModel.Create(context.Background(), &users.User{
+		ID:    userID,
+		State: services.StateMenu,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMenu(Config{
+		Sender: rec,
+		Models: ModelsSet{
+			WhiteList: whiteList.NewMemory(),
+			Users:     usersModel,
+			Keyboards: keyboards.NewMemory(&keyboards.Keyboard{
+				Service:  "vk",
+				Name:     "menu",
+				Keyboard: menuLayout,
+			}),
+		},
+	})
+
+	return m, rec, usersModel
+}
+
+func TestHandleMain(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload *keyboard.Payload
+	}{
+		{name: "no payload"},
+		{name: "unknown command", payload: &keyboard.Payload{Command: "unknown"}},
+		// Account is opened from settings only, stale menu buttons get fresh menu.
+		{name: "account command", payload: &keyboard.Payload{Command: "to_account"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, rec, _ := newMenu(t)
+
+			next, err := m.Handle(context.Background(), &recorder.Message{
+				Peer:    userID,
+				Payload: tt.payload,
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if next != "" {
+				t.Errorf("next = %q, want empty", next)
+			}
+
+			records := rec.Records()
+
+			if len(records) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(records))
+			}
+
+			if records[0].PeerID != userID || records[0].Message != "Главное меню" ||
+				records[0].Keyboard != menuLayout {
+				t.Errorf("sent %+v", records[0])
+			}
+		})
+	}
+}
+
+func TestHandleNavigation(t *testing.T) {
+	tests := []struct {
+		command string
+		state   string
+	}{
+		{command: "to_tasks", state: services.StateTasks},
+		{command: "to_rate", state: services.StateRate},
+		{command: "to_rating", state: services.StateRating},
+		{command: "to_stats", state: services.StateStats},
+		{command: "to_settings", state: services.StateSettings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			m, rec, usersModel := newMenu(t)
+
+			next, err := m.Handle(context.Background(), &recorder.Message{
+				Peer:    userID,
+				Payload: &keyboard.Payload{Command: tt.command},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if next != tt.state {
+				t.Errorf("next = %q, want %q", next, tt.state)
+			}
+
+			user, err := usersModel.Get(context.Background(), userID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.State != tt.state {
+				t.Errorf("state = %q, want %q", user.State, tt.state)
+			}
+
+			if len(rec.Records()) != 0 {
+				t.Errorf("sent %d messages, want none", len(rec.Records()))
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package pool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrStopped pool stopped.
+	ErrStopped = errors.New("pool stopped")
+)
+
+const (
+	defaultSize      = 8
+	defaultQueueSize = 64
+)
+
+// Pool workers pool which shards tasks by peer.
+// Tasks of one peer are processed in order on the same worker,
+// tasks of different peers are processed in parallel.
+type Pool struct {
+	submitted int64
+	processed int64
+	blocked   int64
+	dropped   int64
+	queues    []chan func()
+	done      chan struct{}
+	mu        sync.RWMutex
+	wg        sync.WaitGroup
+	senders   sync.WaitGroup
+	stopped   bool
+}
+
+// Config configuration for Pool.
+type Config struct {
+	Size      int
+	QueueSize int
+}
+
+// Stats pool back-pressure metrics.
+type Stats struct {
+	Size      int   `json:"size"`
+	QueueSize int   `json:"queue_size"`
+	Queued    int   `json:"queued"`
+	MaxQueued int   `json:"max_queued"`
+	Submitted int64 `json:"submitted"`
+	Processed int64 `json:"processed"`
+	Blocked   int64 `json:"blocked"`
+	Dropped   int64 `json:"dropped"`
+}
+
+// NewPool create new instance of Pool and start workers.
+func NewPool(config Config) *Pool {
+	if config.Size <= 0 {
+		config.Size = defaultSize
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+
+	p := &Pool{
+		queues: make([]chan func(), config.Size),
+		done:   make(chan struct{}),
+	}
+
+	for i := range p.queues {
+		p.queues[i] = make(chan func(), config.QueueSize)
+		p.wg.Add(1)
+
+		go p.work(p.queues[i])
+	}
+
+	return p
+}
+
+// Submit put task to peer queue.
+// Blocks while peer queue is full, until pool is stopped.
+func (p *Pool) Submit(peerID int64, task func()) error {
+	p.mu.RLock()
+
+	if p.stopped {
+		p.mu.RUnlock()
+		atomic.AddInt64(&p.dropped, 1)
+		return ErrStopped
+	}
+
+	// Stop closes queues only after senders are done,
+	// so send is safe without holding the lock.
+	p.senders.Add(1)
+	p.mu.RUnlock()
+
+	defer p.senders.Done()
+
+	queue := p.queues[p.shard(peerID)]
+
+	select {
+	case queue <- task:
+		atomic.AddInt64(&p.submitted, 1)
+		return nil
+	default:
+		atomic.AddInt64(&p.blocked, 1)
+	}
+
+	select {
+	case queue <- task:
+		atomic.AddInt64(&p.submitted, 1)
+		return nil
+	case <-p.done:
+		atomic.AddInt64(&p.dropped, 1)
+		return ErrStopped
+	}
+}
+
+// Stop stop accepting tasks and wait queued tasks to finish.
+// Submits blocked on full queues are released with ErrStopped.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+
+	if p.stopped {
+		p.mu.Unlock()
+		p.wg.Wait()
+		return
+	}
+
+	p.stopped = true
+	close(p.done)
+	p.mu.Unlock()
+
+	p.senders.Wait()
+
+	for _, queue := range p.queues {
+		close(queue)
+	}
+
+	p.wg.Wait()
+}
+
+// Stats get pool metrics.
+func (p *Pool) Stats() Stats {
+	stats := Stats{
+		Size:      len(p.queues),
+		QueueSize: cap(p.queues[0]),
+		Submitted: atomic.LoadInt64(&p.submitted),
+		Processed: atomic.LoadInt64(&p.processed),
+		Blocked:   atomic.LoadInt64(&p.blocked),
+		Dropped:   atomic.LoadInt64(&p.dropped),
+	}
+
+	for _, queue := range p.queues {
+		stats.Queued += len(queue)
+
+		if len(queue) > stats.MaxQueued {
+			stats.MaxQueued = len(queue)
+		}
+	}
+
+	return stats
+}
+
+func (p *Pool) work(queue chan func()) {
+	defer p.wg.Done()
+
+	for task := range queue {
+		task()
+		atomic.AddInt64(&p.processed, 1)
+	}
+}
+
+// shard get queue index of peer. Unsigned modulo keeps
+// index in range for any peer, including math.MinInt64.
+func (p *Pool) shard(peerID int64) int {
+	return int(uint64(peerID) % uint64(len(p.queues)))
+}
@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestShard(t *testing.T) {
+	p := NewPool(Config{Size: 3})
+	defer p.Stop()
+
+	peers := []int64{0, 1, -1, 2000000001, math.MaxInt64, math.MinInt64, math.MinInt64 + 1}
+
+	for _, peerID := range peers {
+		shard := p.shard(peerID)
+
+		if shard < 0 || shard >= 3 {
+			t.Errorf("shard(%d) = %d, want in [0, 3)", peerID, shard)
+		}
+	}
+}
+
+func TestSubmitOrder(t *testing.T) {
+	p := NewPool(Config{Size: 4, QueueSize: 1})
+
+	var got []int
+
+	for i := 0; i < 100; i++ {
+		i := i
+
+		err := p.Submit(42, func() {
+			got = append(got, i)
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p.Stop()
+
+	if len(got) != 100 {
+		t.Fatalf("processed %d tasks, want 100", len(got))
+	}
+
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("task %d processed at %d", v, i)
+		}
+	}
+}
+
+func TestStopReleasesBlockedSubmit(t *testing.T) {
+	p := NewPool(Config{Size: 1, QueueSize: 1})
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	// Worker is busy and queue is full, next submit blocks.
+	_ = p.Submit(1, func() {
+		close(started)
+		<-release
+	})
+	<-started
+	_ = p.Submit(1, func() {})
+
+	blocked := make(chan error)
+
+	go func() {
+		blocked <- p.Submit(1, func() {})
+	}()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case err := <-blocked:
+		if err != ErrStopped {
+			t.Errorf("err = %v, want %v", err, ErrStopped)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("submit is not released by stop")
+	}
+
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stop is not finished")
+	}
+
+	err := p.Submit(1, func() {})
+
+	if err != ErrStopped {
+		t.Errorf("err = %v, want %v", err, ErrStopped)
+	}
+
+	stats := p.Stats()
+
+	if stats.Processed != 2 || stats.Dropped != 2 {
+		t.Errorf("stats = %+v, want 2 processed and 2 dropped", stats)
+	}
+}
@@ -0,0 +1,194 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/pseudonym"
+	"github.com/Zetkolink/oracle/scorer"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+)
+
+const leadersLimit = 10
+
+var (
+	menuBtn = &keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Меню",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "menu",
+			},
+		},
+	}
+
+	periodLabels = map[string]string{
+		scores.PeriodDay:   "День",
+		scores.PeriodWeek:  "Неделя",
+		scores.PeriodMonth: "Месяц",
+	}
+)
+
+type Rating struct {
+	sender    services.Sender
+	scorer    *scorer.Scorer
+	pseudonym *pseudonym.Pseudonym
+	models    ModelsSet
+}
+
+type Config struct {
+	Sender    services.Sender
+	Scorer    *scorer.Scorer
+	Pseudonym *pseudonym.Pseudonym
+	Models    ModelsSet
+}
+
+type ModelsSet struct {
+	Users users.Repository
+}
+
+func NewRating(config Config) *Rating {
+	return &Rating{
+		sender:    config.Sender,
+		scorer:    config.Scorer,
+		pseudonym: config.Pseudonym,
+		models:    config.Models,
+	}
+}
+
+func (r *Rating) Handle(ctx context.Context, message services.Message) (string, error) {
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	period := scores.PeriodWeek
+
+	if payload != nil {
+		switch payload.GetCommand() {
+		case "menu":
+			err = r.models.Users.UpdateState(ctx, message.GetPeer(), services.StateMenu)
+
+			if err != nil {
+				return "", err
+			}
+
+			return services.StateMenu, nil
+		case "rating":
+			p, ok := payload.GetParam("period").(string)
+
+			if _, known := periodLabels[p]; ok && known {
+				period = p
+			}
+		}
+	}
+
+	err = r.SendMain(ctx, message.GetUser(), period)
+
+	if err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+// SendMain send user scores and leaders of period.
+func (r *Rating) SendMain(ctx context.Context, user *users.User, period string) error {
+	now := time.Now()
+
+	summary, err := r.scorer.Summary(ctx, user, now)
+
+	if err != nil {
+		return err
+	}
+
+	leaders, err := r.scorer.Leaderboard(ctx, user, period, now, leadersLimit)
+
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Ваши очки\n 📅 - день: %d\n 🗓 - неделя: %d\n 📆 - месяц: %d\n\n",
+		summary.Day, summary.Week, summary.Month))
+	sb.WriteString(fmt.Sprintf("Лидеры (%s)\n", strings.ToLower(periodLabels[period])))
+
+	if len(leaders) == 0 {
+		sb.WriteString(" Пока никто не набрал очков")
+	}
+
+	for i, leader := range leaders {
+		sb.WriteString(fmt.Sprintf(" %d. %s - %d\n", i+1,
+			r.name(ctx, user.ID, leader.UserID), leader.Points))
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: false,
+		Inline:  false,
+		Width:   len(scores.Periods),
+		Height:  1,
+	})
+
+	for i, p := range scores.Periods {
+		color := "primary"
+
+		if p == period {
+			color = "positive"
+		}
+
+		kb.SetButton(0, i, &keyboard.Button{
+			Color: color,
+			Action: keyboard.Action{
+				Label: periodLabels[p],
+				Type:  "text",
+				Payload: keyboard.Payload{
+					Command: "rating",
+					Params: map[string]interface{}{
+						"period": p,
+					},
+				},
+			},
+		})
+	}
+
+	kb.SetFooter(menuBtn)
+
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = r.sender.SendKeyboard(user.ID, sb.String(), kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// name get leader name shown to viewer.
+func (r *Rating) name(ctx context.Context, viewerID int64, userID int64) string {
+	if viewerID == userID {
+		return "Вы"
+	}
+
+	alias, err := r.pseudonym.Alias(ctx, viewerID, userID)
+
+	if err != nil {
+		log.Println(err)
+		return "Аноним"
+	}
+
+	return alias
+}
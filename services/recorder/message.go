@@ -0,0 +1,53 @@
+package recorder
+
+import (
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+)
+
+var _ services.Message = (*Message)(nil)
+
+// Message in-memory inbound message.
+type Message struct {
+	Peer        int64
+	Text        string
+	Payload     *keyboard.Payload
+	User        *users.User
+	Attachments []services.Attachment
+	Location    *services.Location
+}
+
+// GetPeer get message peer.
+func (m *Message) GetPeer() int64 {
+	return m.Peer
+}
+
+// GetText get message text.
+func (m *Message) GetText() string {
+	return m.Text
+}
+
+// GetPayload get message payload.
+func (m *Message) GetPayload() (services.Payload, error) {
+	if m.Payload == nil {
+		return nil, nil
+	}
+
+	return m.Payload, nil
+}
+
+// GetUser get message user.
+func (m *Message) GetUser() *users.User {
+	return m.User
+}
+
+// GetAttachments get message photos and documents.
+func (m *Message) GetAttachments() []services.Attachment {
+	return m.Attachments
+}
+
+// GetLocation get message location, nil if it has no location.
+func (m *Message) GetLocation() *services.Location {
+	return m.Location
+}
@@ -0,0 +1,146 @@
+package recorder
+
+import (
+	"sync"
+
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+)
+
+// Recorder in-memory sender which keeps every outbound message.
+type Recorder struct {
+	mu      sync.Mutex
+	records []*Record
+	users   map[int64]*users.User
+}
+
+// Record recorded outbound message.
+type Record struct {
+	PeerID      int64
+	Message     string
+	Keyboard    string
+	List        []services.ListItem
+	Attachments []services.Attachment
+	Photo       []byte
+	Document    []byte
+}
+
+// NewRecorder create new instance of Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		users: make(map[int64]*users.User),
+	}
+}
+
+// SendMessage record message to user.
+func (r *Recorder) SendMessage(peerID int64, message string) error {
+	r.add(&Record{
+		PeerID:  peerID,
+		Message: message,
+	})
+
+	return nil
+}
+
+// SendKeyboard record message with keyboard to user.
+func (r *Recorder) SendKeyboard(peerID int64, message string, keyboard string) error {
+	r.add(&Record{
+		PeerID:   peerID,
+		Message:  message,
+		Keyboard: keyboard,
+	})
+
+	return nil
+}
+
+// SendList record message with list to user.
+func (r *Recorder) SendList(peerID int64, message string, list []services.ListItem) error {
+	r.add(&Record{
+		PeerID:  peerID,
+		Message: message,
+		List:    list,
+	})
+
+	return nil
+}
+
+// SendAttachments record message with attachments to user.
+func (r *Recorder) SendAttachments(peerID int64, message string, attachments []services.Attachment) error {
+	r.add(&Record{
+		PeerID:      peerID,
+		Message:     message,
+		Attachments: attachments,
+	})
+
+	return nil
+}
+
+// SendPhoto record message with photo to user.
+func (r *Recorder) SendPhoto(peerID int64, message string, photo []byte) error {
+	r.add(&Record{
+		PeerID:  peerID,
+		Message: message,
+		Photo:   photo,
+	})
+
+	return nil
+}
+
+// SendDocument record message with document to user.
+func (r *Recorder) SendDocument(peerID int64, message string, name string, data []byte) error {
+	r.add(&Record{
+		PeerID:   peerID,
+		Message:  message,
+		Document: data,
+	})
+
+	return nil
+}
+
+// SetUser set user returned by GetUser.
+func (r *Recorder) SetUser(user *users.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users[user.ID] = user
+}
+
+// GetUser get user set by SetUser.
+func (r *Recorder) GetUser(userID int64) (*users.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+
+	if !ok {
+		return &users.User{ID: userID, Active: true}, nil
+	}
+
+	return user, nil
+}
+
+// Records get recorded messages.
+func (r *Recorder) Records() []*Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records := make([]*Record, len(r.records))
+	copy(records, r.records)
+
+	return records
+}
+
+// Reset drop recorded messages.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = nil
+}
+
+func (r *Recorder) add(record *Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record)
+}
@@ -0,0 +1,154 @@
+package registrar
+
+import (
+	"context"
+
+	"github.com/Zetkolink/oracle/locator"
+	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/models/whiteList"
+	"github.com/Zetkolink/oracle/services"
+)
+
+const (
+	register = "register"
+)
+
+type Registrar struct {
+	sender   services.Sender
+	profiler services.Profiler
+	locator  *locator.Locator
+	models   ModelsSet
+}
+
+type Config struct {
+	Sender   services.Sender
+	Profiler services.Profiler
+	Locator  *locator.Locator
+	Models   ModelsSet
+}
+
+type ModelsSet struct {
+	WhiteList whiteList.Repository
+	Users     users.Repository
+	Keyboards keyboards.Repository
+}
+
+func NewRegistrar(config Config) *Registrar {
+	return &Registrar{
+		sender:   config.Sender,
+		profiler: config.Profiler,
+		locator:  config.Locator,
+		models:   config.Models,
+	}
+}
+
+func (r *Registrar) Handle(ctx context.Context, message services.Message) (string, error) {
+	ok, err := r.models.WhiteList.Check(ctx, message.GetPeer())
+
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		return "", nil
+	}
+
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	if payload == nil {
+		err := r.SendMain(ctx, message.GetPeer())
+
+		if err != nil {
+			return "", err
+		}
+
+		return "", nil
+	}
+
+	switch payload.GetCommand() {
+	case register:
+		err = r.register(ctx, message.GetPeer())
+
+		if err != nil {
+			return "", err
+		}
+
+		return services.StateMenu, nil
+	default:
+		err := r.SendMain(ctx, message.GetPeer())
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+func (r *Registrar) SendMain(ctx context.Context, peerID int64) error {
+	kb, err := r.models.Keyboards.GetKeyboard(ctx,
+		services.Transport(peerID), "register")
+
+	if err != nil {
+		return err
+	}
+
+	err = r.sender.SendKeyboard(peerID, "Вы готовы?", kb)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Registrar) register(ctx context.Context, peer int64) error {
+	user, err := r.prepareUser(ctx, peer)
+
+	if err != nil {
+		return err
+	}
+
+	err = r.models.Users.Create(ctx, user)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Registrar) prepareUser(ctx context.Context, peer int64) (*users.User, error) {
+	user, err := r.profiler.GetUser(peer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.Timezone = users.DefaultTimezone
+	user.DayStart = users.DefaultDayStart
+	user.State = services.StateMenu
+
+	if user.City == "" {
+		return user, nil
+	}
+
+	place, err := r.locator.City(ctx, user.City)
+
+	if err == locator.ErrNotFound {
+		return user, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.Timezone = place.Timezone
+
+	return user, nil
+}
@@ -0,0 +1,141 @@
+package registrar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/models/whiteList"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/services/recorder"
+)
+
+const (
+	userID         = 1
+	registerLayout = `{"buttons":[]}`
+)
+
+func newRegistrar(t *testing.T, allowed bool) (*Registrar, *recorder.Recorder, *users.Memory) {
+	rec := recorder.NewRecorder()
+	usersModel := users.NewMemory()
+	whiteListModel := whiteList.NewMemory()
+
+	if allowed {
+		err := whiteListModel.Create(context.Background(), &whiteList.Item{UserID: userID})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRegistrar(Config{
+		Sender:   rec,
+		Profiler: rec,
+		Models: ModelsSet{
+			WhiteList: whiteListModel,
+			Users:     usersModel,
+			Keyboards: keyboards.NewMemory(&keyboards.Keyboard{
+				Service:  "vk",
+				Name:     "register",
+				Keyboard: registerLayout,
+			}),
+		},
+	})
+
+	return r, rec, usersModel
+}
+
+func TestHandleNotAllowed(t *testing.T) {
+	r, rec, usersModel := newRegistrar(t, false)
+
+	next, err := r.Handle(context.Background(), &recorder.Message{
+		Peer:    userID,
+		Payload: &keyboard.Payload{Command: register},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next != "" {
+		t.Errorf("next = %q, want empty", next)
+	}
+
+	if len(rec.Records()) != 0 {
+		t.Errorf("sent %d messages, want none", len(rec.Records()))
+	}
+
+	user, err := usersModel.Get(context.Background(), userID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user != nil {
+		t.Errorf("user %+v is registered", user)
+	}
+}
+
+func TestHandleMain(t *testing.T) {
+	r, rec, _ := newRegistrar(t, true)
+
+	next, err := r.Handle(context.Background(), &recorder.Message{Peer: userID})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next != "" {
+		t.Errorf("next = %q, want empty", next)
+	}
+
+	records := rec.Records()
+
+	if len(records) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(records))
+	}
+
+	if records[0].Message != "Вы готовы?" || records[0].Keyboard != registerLayout {
+		t.Errorf("sent %+v", records[0])
+	}
+}
+
+func TestHandleRegister(t *testing.T) {
+	r, rec, usersModel := newRegistrar(t, true)
+
+	rec.SetUser(&users.User{
+		ID:        userID,
+		FirstName: "Иван",
+		Active:    true,
+	})
+
+	next, err := r.Handle(context.Background(), &recorder.Message{
+		Peer:    userID,
+		Payload: &keyboard.Payload{Command: register},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next != services.StateMenu {
+		t.Errorf("next = %q, want %q", next, services.StateMenu)
+	}
+
+	user, err := usersModel.Get(context.Background(), userID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user == nil {
+		t.Fatal("user is not registered")
+	}
+
+	if user.FirstName != "Иван" || user.State != services.StateMenu ||
+		user.Timezone != users.DefaultTimezone || user.DayStart != users.DefaultDayStart {
+		t.Errorf("user = %+v", user)
+	}
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Zetkolink/oracle/services"
+)
+
+var (
+	// ErrUnknownState handler for state not registered.
+	ErrUnknownState = errors.New("unknown state")
+
+	// ErrLoop state transitions loop detected.
+	ErrLoop = errors.New("state transition loop")
+)
+
+// Handler screen handler interface.
+type Handler interface {
+	// Handle handle message and return next state.
+	Handle(ctx context.Context, message services.Message) (string, error)
+}
+
+// HandlerFunc adapter to use functions as handlers.
+type HandlerFunc func(ctx context.Context, message services.Message) (string, error)
+
+// Router routes messages to handlers by user state.
+type Router struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRouter create new instance of Router.
+func NewRouter() *Router {
+	return &Router{
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register register handler for state.
+func (r *Router) Register(state string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[state] = handler
+}
+
+// Handler get handler registered for state.
+func (r *Router) Handler(state string) (Handler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.handlers[state]
+
+	return handler, ok
+}
+
+// Dispatch pass message through handlers starting from state
+// until one of them returns empty state.
+func (r *Router) Dispatch(ctx context.Context, state string, message services.Message) error {
+	visited := make(map[string]struct{})
+
+	for state != "" {
+		if _, ok := visited[state]; ok {
+			return fmt.Errorf("%w: %s", ErrLoop, state)
+		}
+
+		visited[state] = struct{}{}
+
+		handler, ok := r.Handler(state)
+
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownState, state)
+		}
+
+		next, err := handler.Handle(ctx, message)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", state, err)
+		}
+
+		state = next
+	}
+
+	return nil
+}
+
+// Handle call f(ctx, message).
+func (f HandlerFunc) Handle(ctx context.Context, message services.Message) (string, error) {
+	return f(ctx, message)
+}
@@ -1,15 +1,50 @@
 package services
 
-import "github.com/Zetkolink/oracle/models/users"
+import (
+	"context"
+
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+// Screen states.
+const (
+	StateRegister = "register"
+	StateMenu     = "menu"
+	StateTasks    = "tasks"
+	StateRate     = "rate"
+	StateRating   = "rating"
+	StateAppeal   = "appeal"
+	StateStats    = "stats"
+	StateAccount  = "account"
+	StateSettings = "settings"
+)
+
+// Attachment types.
+const (
+	AttachmentPhoto = "photo"
+	AttachmentDoc   = "doc"
+)
 
 // Service services interface.
 type Service interface {
-	// Listen create stream and start listening.
-	Listen() error
+	Sender
+	Profiler
 
+	// Listen create stream and start listening until ctx is done.
+	Listen(ctx context.Context) error
+
+	// Wait wait listening to finish.
+	Wait()
+}
+
+// Profiler services user profiles interface.
+type Profiler interface {
 	// GetUser get user info from service.
 	GetUser(userID int64) (*users.User, error)
+}
 
+// Sender services outbound messages interface.
+type Sender interface {
 	// SendMessage send message to user.
 	SendMessage(peerID int64, message string) error
 
@@ -18,6 +53,16 @@ type Service interface {
 
 	// SendList send message with list to user.
 	SendList(peerID int64, message string, list []ListItem) error
+
+	// SendAttachments send message with attachments to user.
+	// Attachments of other services are skipped.
+	SendAttachments(peerID int64, message string, attachments []Attachment) error
+
+	// SendPhoto upload PNG photo and send it with message to user.
+	SendPhoto(peerID int64, message string, photo []byte) error
+
+	// SendDocument upload file and send it with message to user.
+	SendDocument(peerID int64, message string, name string, data []byte) error
 }
 
 // Message services messages interface.
@@ -31,8 +76,14 @@ type Message interface {
 	// GetPayload get message payload.
 	GetPayload() (Payload, error)
 
-	// GetPayload get message payload.
+	// GetUser get message user, nil if user is not registered.
 	GetUser() *users.User
+
+	// GetAttachments get message photos and documents.
+	GetAttachments() []Attachment
+
+	// GetLocation get message location, nil if it has no location.
+	GetLocation() *Location
 }
 
 // Payload message payload interface.
@@ -44,6 +95,19 @@ type Payload interface {
 	GetParam(key string) interface{}
 }
 
+// Attachment message attachment stored by service.
+type Attachment struct {
+	Service string `json:"service"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+}
+
+// Location geographic point sent by user.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
 type ListItem interface {
 	GetItem() interface{}
 	GetLabel() string
@@ -0,0 +1,731 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Zetkolink/oracle/locator"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/state"
+	"github.com/go-redis/redis/v8"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	settings = "settings"
+
+	inputCity = "city"
+
+	autoHour = -1
+)
+
+// Reminder kinds.
+const (
+	reminderMorning  = "morning"
+	reminderEvening  = "evening"
+	reminderPlanning = "planning"
+)
+
+var (
+	menuBtn = &keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Меню",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "menu",
+			},
+		},
+	}
+
+	backBtn = &keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Назад",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "settings",
+			},
+		},
+	}
+)
+
+// timezone timezone offered in list.
+type timezone struct {
+	ID    string
+	Label string
+}
+
+var timezones = []timezone{
+	{ID: "Europe/Kaliningrad", Label: "Калининград МСК-1"},
+	{ID: "Europe/Moscow", Label: "Москва МСК"},
+	{ID: "Europe/Samara", Label: "Самара МСК+1"},
+	{ID: "Asia/Yekaterinburg", Label: "Екатеринбург МСК+2"},
+	{ID: "Asia/Omsk", Label: "Омск МСК+3"},
+	{ID: "Asia/Novosibirsk", Label: "Новосибирск МСК+4"},
+	{ID: "Asia/Krasnoyarsk", Label: "Красноярск МСК+4"},
+	{ID: "Asia/Irkutsk", Label: "Иркутск МСК+5"},
+	{ID: "Asia/Yakutsk", Label: "Якутск МСК+6"},
+	{ID: "Asia/Vladivostok", Label: "Владивосток МСК+7"},
+	{ID: "Asia/Magadan", Label: "Магадан МСК+8"},
+	{ID: "Asia/Kamchatka", Label: "Камчатка МСК+9"},
+}
+
+// reminder reminder kind with hours offered to choose.
+type reminder struct {
+	Kind  string
+	Label string
+	From  int
+	To    int
+}
+
+var reminders = []reminder{
+	{Kind: reminderMorning, Label: "Список задач", From: 5, To: 12},
+	{Kind: reminderEvening, Label: "Отметка задач", From: 16, To: 23},
+	{Kind: reminderPlanning, Label: "Планирование", From: 16, To: 23},
+}
+
+type Settings struct {
+	sender      services.Sender
+	locator     *locator.Locator
+	redisClient *redis.Client
+	notificator *notificator.Notificator
+	models      ModelsSet
+}
+
+type Config struct {
+	Sender      services.Sender
+	Locator     *locator.Locator
+	RedisClient *redis.Client
+	Notificator *notificator.Notificator
+	Models      ModelsSet
+}
+
+type ModelsSet struct {
+	Users users.Repository
+}
+
+type StateParams struct {
+	Input string `json:"input"`
+}
+
+func NewSettings(config Config) *Settings {
+	return &Settings{
+		sender:      config.Sender,
+		locator:     config.Locator,
+		redisClient: config.RedisClient,
+		notificator: config.Notificator,
+		models:      config.Models,
+	}
+}
+
+func (s *Settings) Handle(ctx context.Context, message services.Message) (string, error) {
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	st, err := state.NewState(ctx, message.GetPeer(), settings, s.redisClient)
+
+	if err != nil {
+		return "", err
+	}
+
+	var params StateParams
+
+	err = mapstructure.Decode(st.Params, &params)
+
+	if err != nil {
+		return "", err
+	}
+
+	peerID := message.GetPeer()
+
+	if location := message.GetLocation(); location != nil {
+		err = s.setCoordinates(ctx, peerID, location, st)
+
+		if err != nil {
+			return "", err
+		}
+
+		return "", nil
+	}
+
+	var command string
+
+	if payload != nil {
+		command = payload.GetCommand()
+	}
+
+	switch command {
+	case "menu":
+		return s.toState(ctx, peerID, services.StateMenu, st)
+	case "to_account":
+		return s.toState(ctx, peerID, services.StateAccount, st)
+	case "city":
+		err = st.SetParams(ctx, StateParams{
+			Input: inputCity,
+		})
+
+		if err != nil {
+			return "", err
+		}
+
+		err = s.askCity(peerID)
+	case "timezones":
+		err = s.sendTimezones(peerID)
+	case "timezone":
+		tz, _ := payload.GetParam("timezone").(string)
+		err = s.setTimezone(ctx, peerID, tz)
+	case "day_starts":
+		err = s.sendDayStarts(peerID)
+	case "day_start":
+		hour, ok := payload.GetParam("hour").(float64)
+
+		if !ok {
+			err = s.sendDayStarts(peerID)
+			break
+		}
+
+		err = s.setDayStart(ctx, peerID, int(hour))
+	case "reminders":
+		err = s.sendReminders(ctx, peerID)
+	case "reminder":
+		kind, _ := payload.GetParam("kind").(string)
+		err = s.sendHours(ctx, peerID, kind)
+	case "reminder_hour":
+		kind, _ := payload.GetParam("kind").(string)
+		hour, ok := payload.GetParam("hour").(float64)
+
+		if !ok {
+			err = s.sendReminders(ctx, peerID)
+			break
+		}
+
+		err = s.setReminder(ctx, peerID, kind, int(hour))
+	default:
+		text := strings.TrimSpace(message.GetText())
+
+		if payload == nil && params.Input == inputCity && text != "" {
+			err = s.setCity(ctx, peerID, text, st)
+			break
+		}
+
+		st.Clear(ctx)
+		err = s.SendMain(ctx, peerID, "")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+// SendMain send user settings.
+func (s *Settings) SendMain(ctx context.Context, peerID int64, note string) error {
+	user, err := s.models.Users.Get(ctx, peerID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   2,
+		Height:  3,
+	})
+
+	kb.SetButton(0, 0, s.button("Город", "primary", "city", nil))
+	kb.SetButton(0, 1, s.button("Часовой пояс", "primary", "timezones", nil))
+	kb.SetButton(1, 0, s.button("Напоминания", "primary", "reminders", nil))
+	kb.SetButton(1, 1, s.button("Начало дня", "primary", "day_starts", nil))
+	kb.SetButton(2, 0, s.button("Удалить аккаунт", "negative", "to_account", nil))
+	kb.SetFooter(menuBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	city := user.City
+
+	if city == "" {
+		city = "не указан"
+	}
+
+	text := fmt.Sprintf("Город - %s\nЧасовой пояс - %s", city, user.Timezone)
+	uTime, err := user.GetTime()
+
+	if err == nil {
+		text += fmt.Sprintf(" (сейчас %s)", uTime.Format("15:04"))
+	}
+
+	text += fmt.Sprintf("\nНачало дня - %s", s.hourLabel(&user.DayStart))
+
+	for _, r := range reminders {
+		text += fmt.Sprintf("\n%s - %s", r.Label, s.hourLabel(s.hour(user, r.Kind)))
+	}
+
+	text += "\n\nЧасовой пояс можно определить по геопозиции, просто отправьте её"
+
+	if note != "" {
+		text = note + "\n\n" + text
+	}
+
+	err = s.sender.SendKeyboard(peerID, text, kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Settings) askCity(peerID int64) error {
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  0,
+	})
+
+	kb.SetFooter(backBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendKeyboard(peerID, "Напишите название города", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Settings) setCity(ctx context.Context, peerID int64, city string, st *state.State) error {
+	place, err := s.locator.City(ctx, city)
+
+	if err == locator.ErrNotFound {
+		return s.sender.SendMessage(peerID, "Город не найден, попробуйте написать иначе")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = s.models.Users.UpdateLocation(ctx, peerID, place.City, place.Timezone)
+
+	if err != nil {
+		return err
+	}
+
+	st.Clear(ctx)
+
+	s.planReminders(ctx, peerID)
+
+	return s.SendMain(ctx, peerID, "Город изменён")
+}
+
+func (s *Settings) setCoordinates(ctx context.Context, peerID int64,
+	location *services.Location, st *state.State) error {
+
+	place, err := s.locator.Coordinates(ctx, location.Latitude, location.Longitude)
+
+	if err == locator.ErrNotFound {
+		return s.sender.SendMessage(peerID, "Не удалось определить часовой пояс по геопозиции")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	user, err := s.models.Users.Get(ctx, peerID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	if place.City == "" {
+		place.City = user.City
+	}
+
+	err = s.models.Users.UpdateLocation(ctx, peerID, place.City, place.Timezone)
+
+	if err != nil {
+		return err
+	}
+
+	st.Clear(ctx)
+
+	s.planReminders(ctx, peerID)
+
+	return s.SendMain(ctx, peerID, "Часовой пояс определён по геопозиции")
+}
+
+func (s *Settings) sendTimezones(peerID int64) error {
+	const width = 3
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   width,
+		Height:  (len(timezones) + width - 1) / width,
+	})
+
+	for i, tz := range timezones {
+		kb.SetButton(i/width, i%width, s.button(tz.Label, "primary", "timezone",
+			map[string]interface{}{
+				"timezone": tz.ID,
+			}))
+	}
+
+	kb.SetFooter(backBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendKeyboard(peerID, "Выберите часовой пояс", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Settings) setTimezone(ctx context.Context, peerID int64, tz string) error {
+	_, err := time.LoadLocation(tz)
+
+	if tz == "" || err != nil {
+		return s.sendTimezones(peerID)
+	}
+
+	user, err := s.models.Users.Get(ctx, peerID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	err = s.models.Users.UpdateLocation(ctx, peerID, user.City, tz)
+
+	if err != nil {
+		return err
+	}
+
+	s.planReminders(ctx, peerID)
+
+	return s.SendMain(ctx, peerID, "Часовой пояс изменён")
+}
+
+func (s *Settings) sendDayStarts(peerID int64) error {
+	const width = 4
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   width,
+		Height:  24 / width,
+	})
+
+	for hour := 0; hour < 24; hour++ {
+		hour := hour
+
+		kb.SetButton(hour/width, hour%width, s.button(s.hourLabel(&hour), "primary", "day_start",
+			map[string]interface{}{
+				"hour": hour,
+			}))
+	}
+
+	kb.SetFooter(backBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendKeyboard(peerID, "Выберите час, с которого начинается ваш день. "+
+		"Уже запланированные задачи сохранят прежние границы дня", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Settings) setDayStart(ctx context.Context, peerID int64, hour int) error {
+	if hour < 0 || hour > 23 {
+		return s.sendDayStarts(peerID)
+	}
+
+	err := s.models.Users.UpdateDayStart(ctx, peerID, hour)
+
+	if err != nil {
+		return err
+	}
+
+	s.planReminders(ctx, peerID)
+
+	return s.SendMain(ctx, peerID, "Начало дня изменено")
+}
+
+func (s *Settings) sendReminders(ctx context.Context, peerID int64) error {
+	user, err := s.models.Users.Get(ctx, peerID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  len(reminders),
+	})
+
+	text := "Выберите напоминание. Без заданного часа напоминания " +
+		"приходят в зависимости от времени подъёма"
+
+	for i, r := range reminders {
+		kb.SetButton(i, 0, s.button(fmt.Sprintf("%s - %s", r.Label,
+			s.hourLabel(s.hour(user, r.Kind))), "primary", "reminder",
+			map[string]interface{}{
+				"kind": r.Kind,
+			}))
+	}
+
+	kb.SetFooter(backBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendKeyboard(peerID, text, kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Settings) sendHours(ctx context.Context, peerID int64, kind string) error {
+	const width = 3
+
+	r, ok := s.reminder(kind)
+
+	if !ok {
+		return s.SendMain(ctx, peerID, "")
+	}
+
+	hours := []int{autoHour}
+
+	for hour := r.From; hour <= r.To; hour++ {
+		hours = append(hours, hour)
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   width,
+		Height:  (len(hours) + width - 1) / width,
+	})
+
+	for i, hour := range hours {
+		hour := hour
+		var h *int
+
+		if hour != autoHour {
+			h = &hour
+		}
+
+		kb.SetButton(i/width, i%width, s.button(s.hourLabel(h), "primary", "reminder_hour",
+			map[string]interface{}{
+				"kind": kind,
+				"hour": hour,
+			}))
+	}
+
+	kb.SetFooter(&keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Назад",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "reminders",
+			},
+		},
+	})
+
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendKeyboard(peerID, fmt.Sprintf("%s - выберите час", r.Label), kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Settings) setReminder(ctx context.Context, peerID int64, kind string, hour int) error {
+	r, ok := s.reminder(kind)
+
+	if !ok || hour != autoHour && (hour < r.From || hour > r.To) {
+		return s.sendReminders(ctx, peerID)
+	}
+
+	user, err := s.models.Users.Get(ctx, peerID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	var h *int
+
+	if hour != autoHour {
+		h = &hour
+	}
+
+	rem := user.Reminders
+
+	switch kind {
+	case reminderMorning:
+		rem.Morning = h
+	case reminderEvening:
+		rem.Evening = h
+	case reminderPlanning:
+		rem.Planning = h
+	}
+
+	err = s.models.Users.UpdateReminders(ctx, peerID, rem)
+
+	if err != nil {
+		return err
+	}
+
+	s.planReminders(ctx, peerID)
+
+	return s.SendMain(ctx, peerID, "Напоминание изменено")
+}
+
+// planReminders replan user reminders after settings change,
+// failure is not fatal as reminders are replanned hourly.
+func (s *Settings) planReminders(ctx context.Context, peerID int64) {
+	user, err := s.models.Users.Get(ctx, peerID)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if user == nil {
+		return
+	}
+
+	err = s.notificator.PlanUser(ctx, user)
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (s *Settings) toState(ctx context.Context, peerID int64, st string,
+	sState *state.State) (string, error) {
+
+	sState.Clear(ctx)
+
+	err := s.models.Users.UpdateState(ctx, peerID, st)
+
+	if err != nil {
+		return "", err
+	}
+
+	return st, nil
+}
+
+func (s *Settings) reminder(kind string) (reminder, bool) {
+	for _, r := range reminders {
+		if r.Kind == kind {
+			return r, true
+		}
+	}
+
+	return reminder{}, false
+}
+
+func (s *Settings) hour(user *users.User, kind string) *int {
+	switch kind {
+	case reminderMorning:
+		return user.Reminders.Morning
+	case reminderEvening:
+		return user.Reminders.Evening
+	case reminderPlanning:
+		return user.Reminders.Planning
+	}
+
+	return nil
+}
+
+func (s *Settings) hourLabel(hour *int) string {
+	if hour == nil {
+		return "авто"
+	}
+
+	return fmt.Sprintf("%02d:00", *hour)
+}
+
+func (s *Settings) button(label string, color string, command string,
+	params map[string]interface{}) *keyboard.Button {
+
+	return &keyboard.Button{
+		Color: color,
+		Action: keyboard.Action{
+			Label: label,
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: command,
+				Params:  params,
+			},
+		},
+	}
+}
@@ -0,0 +1,224 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Zetkolink/oracle/exporter"
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/statistics"
+)
+
+var (
+	menuBtn = &keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Меню",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "menu",
+			},
+		},
+	}
+)
+
+type Stats struct {
+	sender     services.Sender
+	statistics *statistics.Statistics
+	exporter   *exporter.Exporter
+	models     ModelsSet
+}
+
+type Config struct {
+	Sender     services.Sender
+	Statistics *statistics.Statistics
+	Exporter   *exporter.Exporter
+	Models     ModelsSet
+}
+
+type ModelsSet struct {
+	Users     users.Repository
+	GoalTypes goalTypes.Repository
+}
+
+func NewStats(config Config) *Stats {
+	return &Stats{
+		sender:     config.Sender,
+		statistics: config.Statistics,
+		exporter:   config.Exporter,
+		models:     config.Models,
+	}
+}
+
+func (s *Stats) Handle(ctx context.Context, message services.Message) (string, error) {
+	payload, err := message.GetPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	if payload != nil {
+		switch payload.GetCommand() {
+		case "menu":
+			err = s.models.Users.UpdateState(ctx, message.GetPeer(), services.StateMenu)
+
+			if err != nil {
+				return "", err
+			}
+
+			return services.StateMenu, nil
+		case "export":
+			format, _ := payload.GetParam("format").(string)
+
+			name, data, err := s.exporter.File(ctx, message.GetPeer(), format)
+
+			if err != nil {
+				return "", err
+			}
+
+			err = s.sender.SendDocument(message.GetPeer(), "Ваши данные", name, data)
+
+			if err != nil {
+				return "", err
+			}
+
+			return "", nil
+		}
+	}
+
+	err = s.SendReport(ctx, message.GetUser(), "Статистика")
+
+	if err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+// SendReport send user statistics with trend chart.
+func (s *Stats) SendReport(ctx context.Context, user *users.User, title string) error {
+	report, err := s.statistics.Report(ctx, user, time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	types, err := s.models.GoalTypes.List(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(title + "\n\n")
+	s.writePeriod(&sb, "Неделя", report.Week, types)
+	s.writePeriod(&sb, "Месяц", report.Month, types)
+	s.writePeriod(&sb, "За всё время", report.All, types)
+	s.writeTrend(&sb, report.Trend)
+
+	chart, err := statistics.Chart(report.Trend)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendPhoto(user.ID, fmt.Sprintf(
+		"Выполнение по неделям за %d нед.", len(report.Trend)), chart)
+
+	if err != nil {
+		return err
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: false,
+		Inline:  false,
+		Width:   2,
+		Height:  1,
+	})
+
+	for i, format := range []string{exporter.FormatCSV, exporter.FormatJSON} {
+		kb.SetButton(0, i, &keyboard.Button{
+			Color: "primary",
+			Action: keyboard.Action{
+				Label: "Экспорт " + strings.ToUpper(format),
+				Type:  "text",
+				Payload: keyboard.Payload{
+					Command: "export",
+					Params: map[string]interface{}{
+						"format": format,
+					},
+				},
+			},
+		})
+	}
+
+	kb.SetFooter(menuBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.SendKeyboard(user.ID, sb.String(), kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Stats) writePeriod(sb *strings.Builder, label string,
+	period *statistics.Period, types []*goalTypes.GoalType) {
+
+	sb.WriteString(fmt.Sprintf("%s - выполнено %d%%\n", label, period.Rate()))
+	sb.WriteString(fmt.Sprintf(" 🍏 %d  🍎 %d  🎯 %d  📝 %d\n",
+		period.Complete, period.Failed, period.InProgress, period.Soon))
+
+	for _, gType := range types {
+		counts, ok := period.ByType[gType.ID]
+
+		if !ok || counts.Finished() == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf(" %s - %d/%d (%d%%)\n", gType.Name,
+			counts.Complete, counts.Finished(), counts.Rate()))
+	}
+
+	sb.WriteString("\n")
+}
+
+func (s *Stats) writeTrend(sb *strings.Builder, trend []*statistics.Period) {
+	sb.WriteString("Динамика по неделям\n")
+
+	var prev *statistics.Period
+
+	for _, week := range trend {
+		if week.Finished() == 0 {
+			sb.WriteString(fmt.Sprintf(" %s - нет данных\n", week.Start.Format("02.01")))
+			continue
+		}
+
+		mark := ""
+
+		if prev != nil {
+			switch {
+			case week.Rate() > prev.Rate():
+				mark = " 📈"
+			case week.Rate() < prev.Rate():
+				mark = " 📉"
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf(" %s - %d%%%s\n", week.Start.Format("02.01"),
+			week.Rate(), mark))
+		prev = week
+	}
+}
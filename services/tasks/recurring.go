@@ -0,0 +1,782 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/templates"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/state"
+)
+
+const maxInterval = 365
+
+var (
+	weekdays = []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+		time.Friday, time.Saturday, time.Sunday,
+	}
+
+	weekdayNames = map[time.Weekday]string{
+		time.Monday:    "Пн",
+		time.Tuesday:   "Вт",
+		time.Wednesday: "Ср",
+		time.Thursday:  "Чт",
+		time.Friday:    "Пт",
+		time.Saturday:  "Сб",
+		time.Sunday:    "Вс",
+	}
+
+	ruleNames = []struct {
+		Rule  string
+		Label string
+	}{
+		{templates.RuleDaily, "Каждый день"},
+		{templates.RuleWeekdays, "По будням"},
+		{templates.RuleDays, "По дням недели"},
+		{templates.RuleInterval, "Каждые N дней"},
+	}
+
+	errTemplateNotFound = errors.New("template not found")
+)
+
+// handleTemplate handle recurring goals commands.
+func (t *Tasks) handleTemplate(ctx context.Context, message services.Message,
+	payload services.Payload, st *state.State, params StateParams, command string) error {
+
+	user := message.GetUser()
+
+	switch command {
+	case "recurring":
+		st.Clear(ctx)
+
+		return t.sendTemplates(ctx, user)
+	case "template":
+		tpl, err := t.payloadTemplate(ctx, user, payload)
+
+		if err != nil {
+			return err
+		}
+
+		return t.sendTemplate(ctx, user, tpl)
+	case "template_pause":
+		tpl, err := t.payloadTemplate(ctx, user, payload)
+
+		if err != nil {
+			return err
+		}
+
+		if !tpl.Paused {
+			err = t.manager.Unschedule(ctx, user, tpl)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		tpl.Paused = !tpl.Paused
+
+		err = t.models.Templates.Update(ctx, tpl)
+
+		if err != nil {
+			return err
+		}
+
+		if !tpl.Paused {
+			err = t.manager.Materialize(ctx, user, tpl)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return t.sendTemplate(ctx, user, tpl)
+	case "template_cancel":
+		tpl, err := t.payloadTemplate(ctx, user, payload)
+
+		if err != nil {
+			return err
+		}
+
+		err = t.manager.Unschedule(ctx, user, tpl)
+
+		if err != nil {
+			return err
+		}
+
+		err = t.models.Templates.Delete(ctx, tpl.ID)
+
+		if err != nil {
+			return err
+		}
+
+		err = t.sender.SendMessage(user.ID, "Повтор отменён")
+
+		if err != nil {
+			return err
+		}
+
+		return t.sendTemplates(ctx, user)
+	case "template_edit":
+		tpl, err := t.payloadTemplate(ctx, user, payload)
+
+		if err != nil {
+			return err
+		}
+
+		return t.templateGoal(ctx, user, st, StateParams{
+			Template: tpl.ID,
+			Type:     tpl.Type,
+		})
+	case "new_template":
+		st.Clear(ctx)
+
+		return t.templateType(ctx, user)
+	case "template_type":
+		typeParam, ok := payload.GetParam("goal_type").(float64)
+
+		if !ok {
+			return errors.New("goal type not found")
+		}
+
+		gTypeID := int64(typeParam)
+
+		list, err := t.models.Templates.ListByUser(ctx, user.ID)
+
+		if err != nil {
+			return err
+		}
+
+		for _, tpl := range list {
+			if tpl.Type == gTypeID {
+				err = t.sender.SendMessage(user.ID, "Для этого типа уже есть повтор")
+
+				if err != nil {
+					return err
+				}
+
+				return t.sendTemplate(ctx, user, tpl)
+			}
+		}
+
+		return t.templateGoal(ctx, user, st, StateParams{Type: gTypeID})
+	case "template_goal":
+		if params.Type == 0 {
+			return errors.New("not found params")
+		}
+
+		goalParam, ok := payload.GetParam("goal").(float64)
+
+		if !ok {
+			return errors.New("goal not found")
+		}
+
+		params.Goal = int64(goalParam)
+
+		return t.templateRule(ctx, user.ID, st, params)
+	case "template_input":
+		if params.Type == 0 {
+			return errors.New("not found params")
+		}
+
+		text := strings.TrimSpace(message.GetText())
+
+		if text == "" {
+			return t.sender.SendMessage(user.ID, "Введите")
+		}
+
+		goalID, err := t.models.Goals.Create(ctx, &goals.Goal{
+			Type:        params.Type,
+			Description: text,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		params.Goal = goalID
+
+		return t.templateRule(ctx, user.ID, st, params)
+	case "template_rule":
+		if params.Type == 0 || params.Goal == 0 {
+			return errors.New("not found params")
+		}
+
+		rule, ok := payload.GetParam("rule").(string)
+
+		if !ok {
+			return errors.New("rule not found")
+		}
+
+		params.Rule = rule
+
+		switch rule {
+		case templates.RuleDaily, templates.RuleWeekdays:
+			return t.saveTemplate(ctx, user, st, params, 0)
+		case templates.RuleDays:
+			params.Weekdays = 0
+
+			err := st.SetParams(ctx, params)
+
+			if err != nil {
+				return err
+			}
+
+			return t.templateWeekdays(user.ID, params.Weekdays)
+		case templates.RuleInterval:
+			params.Command = "template_interval"
+
+			err := st.SetParams(ctx, params)
+
+			if err != nil {
+				return err
+			}
+
+			return t.sender.SendMessage(user.ID, "Через сколько дней повторять?")
+		}
+
+		return errors.New("unknown rule")
+	case "template_day":
+		if params.Rule != templates.RuleDays {
+			return errors.New("not found params")
+		}
+
+		dayParam, ok := payload.GetParam("day").(float64)
+
+		if !ok {
+			return errors.New("day not found")
+		}
+
+		params.Weekdays ^= 1 << uint(dayParam)
+
+		err := st.SetParams(ctx, params)
+
+		if err != nil {
+			return err
+		}
+
+		return t.templateWeekdays(user.ID, params.Weekdays)
+	case "template_days_done":
+		if params.Rule != templates.RuleDays {
+			return errors.New("not found params")
+		}
+
+		if params.Weekdays == 0 {
+			err := t.sender.SendMessage(user.ID, "Выберите хотя бы один день")
+
+			if err != nil {
+				return err
+			}
+
+			return t.templateWeekdays(user.ID, params.Weekdays)
+		}
+
+		return t.saveTemplate(ctx, user, st, params, 0)
+	case "template_interval":
+		interval, err := strconv.Atoi(strings.TrimSpace(message.GetText()))
+
+		if err != nil || interval < 1 || interval > maxInterval {
+			return t.sender.SendMessage(user.ID,
+				fmt.Sprintf("Введите число от 1 до %d", maxInterval))
+		}
+
+		return t.saveTemplate(ctx, user, st, params, int64(interval))
+	}
+
+	return nil
+}
+
+// saveTemplate create template or replace edited one,
+// goals planned by previous version are rescheduled.
+func (t *Tasks) saveTemplate(ctx context.Context, user *users.User,
+	st *state.State, params StateParams, interval int64) error {
+
+	uDay, err := user.Day(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	tpl := &templates.Template{UserID: user.ID}
+
+	if params.Template != 0 {
+		tpl, err = t.userTemplate(ctx, user, params.Template)
+
+		if err != nil {
+			return err
+		}
+
+		err = t.manager.Unschedule(ctx, user, tpl)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	tpl.GoalID = params.Goal
+	tpl.Type = params.Type
+	tpl.Rule = params.Rule
+	tpl.Weekdays = params.Weekdays
+	tpl.Interval = interval
+	tpl.StartDate = templates.Day(*uDay)
+
+	if tpl.ID == 0 {
+		err = t.models.Templates.Create(ctx, tpl)
+	} else {
+		err = t.models.Templates.Update(ctx, tpl)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	st.Clear(ctx)
+
+	if !tpl.Paused {
+		err = t.manager.Materialize(ctx, user, tpl)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return t.sendTemplate(ctx, user, tpl)
+}
+
+func (t *Tasks) payloadTemplate(ctx context.Context, user *users.User,
+	payload services.Payload) (*templates.Template, error) {
+
+	if payload == nil {
+		return nil, errTemplateNotFound
+	}
+
+	tplParam, ok := payload.GetParam("template").(float64)
+
+	if !ok {
+		return nil, errTemplateNotFound
+	}
+
+	return t.userTemplate(ctx, user, int64(tplParam))
+}
+
+func (t *Tasks) userTemplate(ctx context.Context, user *users.User,
+	id int64) (*templates.Template, error) {
+
+	tpl, err := t.models.Templates.Get(ctx, id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errTemplateNotFound
+		}
+
+		return nil, err
+	}
+
+	if tpl.UserID != user.ID {
+		return nil, errTemplateNotFound
+	}
+
+	return tpl, nil
+}
+
+// describeTemplate get template goal and rule description.
+func (t *Tasks) describeTemplate(ctx context.Context, tpl *templates.Template) (string, error) {
+	gType, err := t.models.GoalTypes.Get(ctx, tpl.Type)
+
+	if err != nil {
+		return "", err
+	}
+
+	goal, err := t.models.Goals.Get(ctx, tpl.GoalID)
+
+	if err != nil {
+		return "", err
+	}
+
+	var rule string
+
+	switch tpl.Rule {
+	case templates.RuleDaily:
+		rule = "Каждый день"
+	case templates.RuleWeekdays:
+		rule = "По будням"
+	case templates.RuleDays:
+		var days []string
+
+		for _, day := range weekdays {
+			if tpl.Weekdays&(1<<uint(day)) != 0 {
+				days = append(days, weekdayNames[day])
+			}
+		}
+
+		rule = strings.Join(days, ", ")
+	case templates.RuleInterval:
+		rule = fmt.Sprintf("Каждые %d дн.", tpl.Interval)
+	}
+
+	message := fmt.Sprintf("%s\n 💡 %s\n 🔁 %s", gType.Name, goal.Description, rule)
+
+	if tpl.Paused {
+		message += "\n ⏸ На паузе"
+	}
+
+	return message, nil
+}
+
+func (t *Tasks) sendTemplates(ctx context.Context, user *users.User) error {
+	list, err := t.models.Templates.ListByUser(ctx, user.ID)
+
+	if err != nil {
+		return err
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: false,
+		Inline:  false,
+		Width:   1,
+		Height:  len(list) + 1,
+	})
+
+	message := "Повторяющиеся задачи\n\n"
+
+	if len(list) == 0 {
+		message = "У вас нет повторяющихся задач"
+	}
+
+	for i, tpl := range list {
+		description, err := t.describeTemplate(ctx, tpl)
+
+		if err != nil {
+			return err
+		}
+
+		message += description + "\n\n"
+
+		gType, err := t.models.GoalTypes.Get(ctx, tpl.Type)
+
+		if err != nil {
+			return err
+		}
+
+		kb.SetButton(i, 0, &keyboard.Button{
+			Color: "primary",
+			Action: keyboard.Action{
+				Label: gType.Name,
+				Type:  "text",
+				Payload: keyboard.Payload{
+					Command: "template",
+					Params: map[string]interface{}{
+						"template": tpl.ID,
+					},
+				},
+			},
+		})
+	}
+
+	kb.SetButton(len(list), 0, &keyboard.Button{
+		Color: "positive",
+		Action: keyboard.Action{
+			Label: "Новый повтор",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "new_template",
+			},
+		},
+	})
+
+	kb.SetFooter(rejectBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendKeyboard(user.ID, message, kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *Tasks) sendTemplate(ctx context.Context, user *users.User, tpl *templates.Template) error {
+	message, err := t.describeTemplate(ctx, tpl)
+
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"template": tpl.ID,
+	}
+
+	pauseLabel := "Пауза"
+
+	if tpl.Paused {
+		pauseLabel = "Возобновить"
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: false,
+		Inline:  false,
+		Width:   3,
+		Height:  1,
+	})
+
+	kb.SetButton(0, 0, &keyboard.Button{
+		Color: "primary",
+		Action: keyboard.Action{
+			Label: pauseLabel,
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "template_pause",
+				Params:  params,
+			},
+		},
+	})
+
+	kb.SetButton(0, 1, &keyboard.Button{
+		Color: "primary",
+		Action: keyboard.Action{
+			Label: "Изменить",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "template_edit",
+				Params:  params,
+			},
+		},
+	})
+
+	kb.SetButton(0, 2, &keyboard.Button{
+		Color: "negative",
+		Action: keyboard.Action{
+			Label: "Отменить",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "template_cancel",
+				Params:  params,
+			},
+		},
+	})
+
+	kb.SetFooter(&keyboard.Button{
+		Color: "secondary",
+		Action: keyboard.Action{
+			Label: "Повторы",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "recurring",
+			},
+		},
+	})
+
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendKeyboard(user.ID, message, kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *Tasks) templateType(ctx context.Context, user *users.User) error {
+	types, err := t.models.GoalTypes.List(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  len(types),
+	})
+
+	for i, gType := range types {
+		kb.SetButton(i, 0, &keyboard.Button{
+			Color: "primary",
+			Action: keyboard.Action{
+				Label: gType.Name,
+				Type:  "text",
+				Payload: keyboard.Payload{
+					Command: "template_type",
+					Params: map[string]interface{}{
+						"goal_type": gType.ID,
+					},
+				},
+			},
+		})
+	}
+
+	kb.SetFooter(rejectBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendKeyboard(user.ID, "Выберите тип", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// templateGoal ask goal of template from list or as text.
+func (t *Tasks) templateGoal(ctx context.Context, user *users.User,
+	st *state.State, params StateParams) error {
+
+	gType, err := t.models.GoalTypes.Get(ctx, params.Type)
+
+	if err != nil {
+		return err
+	}
+
+	if !gType.FromList {
+		params.Command = "template_input"
+	}
+
+	err = st.SetParams(ctx, params)
+
+	if err != nil {
+		return err
+	}
+
+	if gType.FromList {
+		return t.choseGoal(ctx, user.ID, gType, "template_goal")
+	}
+
+	err = t.sender.SendMessage(user.ID, "Введите")
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *Tasks) templateRule(ctx context.Context, peerID int64,
+	st *state.State, params StateParams) error {
+
+	params.Command = ""
+
+	err := st.SetParams(ctx, params)
+
+	if err != nil {
+		return err
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   2,
+		Height:  2,
+	})
+
+	for i, rule := range ruleNames {
+		kb.SetButton(i/2, i%2, &keyboard.Button{
+			Color: "primary",
+			Action: keyboard.Action{
+				Label: rule.Label,
+				Type:  "text",
+				Payload: keyboard.Payload{
+					Command: "template_rule",
+					Params: map[string]interface{}{
+						"rule": rule.Rule,
+					},
+				},
+			},
+		})
+	}
+
+	kb.SetFooter(rejectBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendKeyboard(peerID, "Как часто повторять?", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// templateWeekdays send weekdays keyboard, chosen days are highlighted.
+func (t *Tasks) templateWeekdays(peerID int64, mask int64) error {
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: false,
+		Inline:  false,
+		Width:   4,
+		Height:  2,
+	})
+
+	for i, day := range weekdays {
+		btn := &keyboard.Button{
+			Color: "secondary",
+			Action: keyboard.Action{
+				Label: weekdayNames[day],
+				Type:  "text",
+				Payload: keyboard.Payload{
+					Command: "template_day",
+					Params: map[string]interface{}{
+						"day": int(day),
+					},
+				},
+			},
+		}
+
+		if mask&(1<<uint(day)) != 0 {
+			btn.Color = "positive"
+		}
+
+		kb.SetButton(i/4, i%4, btn)
+	}
+
+	kb.SetButton(1, 3, &keyboard.Button{
+		Color: "primary",
+		Action: keyboard.Action{
+			Label: "Готово",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "template_days_done",
+			},
+		},
+	})
+
+	kb.SetFooter(rejectBtn)
+	kbStr, err := kb.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendKeyboard(peerID, "Выберите дни недели", kbStr)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
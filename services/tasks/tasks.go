@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -10,13 +11,15 @@ import (
 	"github.com/Zetkolink/oracle/models/goalTypes"
 	"github.com/Zetkolink/oracle/models/goals"
 	"github.com/Zetkolink/oracle/models/keyboards"
+	"github.com/Zetkolink/oracle/models/proofs"
+	"github.com/Zetkolink/oracle/models/streaks"
+	"github.com/Zetkolink/oracle/models/templates"
 	"github.com/Zetkolink/oracle/models/userGoals"
 	"github.com/Zetkolink/oracle/models/users"
 	"github.com/Zetkolink/oracle/services"
-	"github.com/Zetkolink/oracle/services/vk/keyboard"
+	"github.com/Zetkolink/oracle/services/keyboard"
 	"github.com/Zetkolink/oracle/state"
 	"github.com/go-redis/redis/v8"
-	"github.com/go-vk-api/vk"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -25,6 +28,17 @@ const (
 )
 
 var (
+	skipProofBtn = &keyboard.Button{
+		Color: "primary",
+		Action: keyboard.Action{
+			Label: "Пропустить",
+			Type:  "text",
+			Payload: keyboard.Payload{
+				Command: "skip_proof",
+			},
+		},
+	}
+
 	rejectBtn = &keyboard.Button{
 		Color: "secondary",
 		Action: keyboard.Action{
@@ -40,35 +54,42 @@ var (
 )
 
 type Tasks struct {
-	vkClient    *vk.Client
+	sender      services.Sender
 	models      ModelsSet
 	manager     *manager.Manager
 	redisClient *redis.Client
 }
 
 type Config struct {
-	VKClient    *vk.Client
+	Sender      services.Sender
 	Models      ModelsSet
 	Manager     *manager.Manager
 	RedisClient *redis.Client
 }
 
 type ModelsSet struct {
-	Users     *users.Model
-	Keyboards *keyboards.Model
-	GoalTypes *goalTypes.Model
-	Goals     *goals.Model
+	Users     users.Repository
+	Keyboards keyboards.Repository
+	GoalTypes goalTypes.Repository
+	Goals     goals.Repository
+	Proofs    proofs.Repository
+	Templates templates.Repository
+	Streaks   streaks.Repository
 }
 
 type StateParams struct {
-	Date    string `json:"date"`
-	Type    int64  `json:"type"`
-	Command string `json:"command"`
+	Date     string `json:"date"`
+	Type     int64  `json:"type"`
+	Command  string `json:"command"`
+	Template int64  `json:"template"`
+	Goal     int64  `json:"goal"`
+	Rule     string `json:"rule"`
+	Weekdays int64  `json:"weekdays"`
 }
 
 func NewTasks(config Config) *Tasks {
 	return &Tasks{
-		vkClient:    config.VKClient,
+		sender:      config.Sender,
 		models:      config.Models,
 		manager:     config.Manager,
 		redisClient: config.RedisClient,
@@ -106,13 +127,13 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 
 	switch command {
 	case "menu":
-		err = t.models.Users.UpdateState(ctx, message.GetPeer(), "menu")
+		err = t.models.Users.UpdateState(ctx, message.GetPeer(), services.StateMenu)
 
 		if err != nil {
 			return "", err
 		}
 
-		return "menu", nil
+		return services.StateMenu, nil
 	case "current_tasks":
 		date, err := message.GetUser().Date(time.Now())
 
@@ -190,8 +211,52 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 
 		gTypeID := int64(typeParam)
 
+		gType, err := t.models.GoalTypes.Get(ctx, gTypeID)
+
+		if err != nil {
+			return "", err
+		}
+
+		uGoal, err := t.manager.GetByType(ctx, message.GetUser(), *date, gTypeID)
+
+		if err != nil {
+			return "", err
+		}
+
+		if uGoal != nil && uGoal.Status != userGoals.StatusComplete {
+			if !gType.RequiresProof {
+				err = t.manager.SetStatus(ctx, message.GetUser(), *date, gTypeID)
+
+				if err != nil {
+					return "", err
+				}
+			}
+
+			err = st.SetParams(ctx, StateParams{
+				Command: "attach_proof",
+				Type:    gTypeID,
+				Date:    date.Format(time.RFC3339),
+			})
+
+			if err != nil {
+				return "", err
+			}
+
+			err = t.askProof(message.GetPeer(), gType)
+
+			if err != nil {
+				return "", err
+			}
+
+			return "", nil
+		}
+
 		err = t.manager.SetStatus(ctx, message.GetUser(), *date, gTypeID)
 
+		if err != nil {
+			return "", err
+		}
+
 		err = t.MarkGoalList(ctx, message.GetUser(), *date)
 
 		if err != nil {
@@ -200,6 +265,124 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 
 		err = t.markType(ctx, *date, message.GetUser())
 
+		if err != nil {
+			return "", err
+		}
+	case "attach_proof":
+		if params.Type == 0 || params.Date == "" {
+			return "", errors.New("not found params")
+		}
+
+		gType, err := t.models.GoalTypes.Get(ctx, params.Type)
+
+		if err != nil {
+			return "", err
+		}
+
+		attachments := message.GetAttachments()
+
+		if len(attachments) == 0 {
+			err = t.askProof(message.GetPeer(), gType)
+
+			if err != nil {
+				return "", err
+			}
+
+			return "", nil
+		}
+
+		date, err := time.Parse(time.RFC3339, params.Date)
+
+		if err != nil {
+			return "", err
+		}
+
+		st.Clear(ctx)
+
+		uGoal, err := t.manager.GetByType(ctx, message.GetUser(), date, params.Type)
+
+		if err != nil {
+			return "", err
+		}
+
+		if uGoal == nil {
+			err = t.SendMain(ctx, message.GetPeer())
+
+			if err != nil {
+				return "", err
+			}
+
+			return "", nil
+		}
+
+		proof := &proofs.Proof{
+			UserGoalID: uGoal.ID,
+			Kind:       proofs.KindCompletion,
+			Text:       message.GetText(),
+		}
+
+		for _, attachment := range attachments {
+			proof.Attachments = append(proof.Attachments, &proofs.Attachment{
+				Service: attachment.Service,
+				Type:    attachment.Type,
+				ID:      attachment.ID,
+			})
+		}
+
+		err = t.models.Proofs.Create(ctx, proof)
+
+		if err != nil {
+			return "", err
+		}
+
+		if uGoal.Status != userGoals.StatusComplete {
+			err = t.manager.SetStatus(ctx, message.GetUser(), date, params.Type)
+
+			if err != nil {
+				return "", err
+			}
+		}
+
+		err = t.MarkGoalList(ctx, message.GetUser(), date)
+
+		if err != nil {
+			return "", err
+		}
+
+		err = t.markType(ctx, date, message.GetUser())
+
+		if err != nil {
+			return "", err
+		}
+	case "skip_proof":
+		if params.Date == "" {
+			return "", errors.New("not found params")
+		}
+
+		date, err := time.Parse(time.RFC3339, params.Date)
+
+		if err != nil {
+			return "", err
+		}
+
+		st.Clear(ctx)
+
+		err = t.MarkGoalList(ctx, message.GetUser(), date)
+
+		if err != nil {
+			return "", err
+		}
+
+		err = t.markType(ctx, date, message.GetUser())
+
+		if err != nil {
+			return "", err
+		}
+	case "rejectBtn":
+		st.Clear(ctx)
+
+		err := t.SendMain(ctx, message.GetPeer())
+
 		if err != nil {
 			return "", err
 		}
@@ -297,7 +480,7 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 		}
 
 		if gType.FromList {
-			err = t.choseGoal(ctx, message.GetPeer(), gType)
+			err = t.choseGoal(ctx, message.GetPeer(), gType, "chose_goal")
 
 			if err != nil {
 				return "", err
@@ -313,11 +496,7 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 			return "", err
 		}
 
-		err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-			"peer_id":   message.GetPeer(),
-			"message":   "Введите",
-			"random_id": 0,
-		}, nil)
+		err = t.sender.SendMessage(message.GetPeer(), "Введите")
 
 		if err != nil {
 			return "", err
@@ -405,6 +584,16 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 		}
 
 		return "", nil
+	case "recurring", "template", "template_pause", "template_edit",
+		"template_cancel", "new_template", "template_type", "template_goal",
+		"template_input", "template_rule", "template_day",
+		"template_days_done", "template_interval":
+
+		err := t.handleTemplate(ctx, message, payload, st, params, command)
+
+		if err != nil {
+			return "", err
+		}
 	default:
 		err := t.SendMain(ctx, message.GetPeer())
 
@@ -417,19 +606,53 @@ func (t *Tasks) Handle(ctx context.Context, message services.Message) (string, e
 }
 
 func (t *Tasks) SendMain(ctx context.Context, peerID int64) error {
-	kb, err := t.models.Keyboards.GetKeyboard(ctx, "vk",
-		"tasks")
+	kb, err := t.models.Keyboards.GetKeyboard(ctx,
+		services.Transport(peerID), "tasks")
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendKeyboard(peerID, "Задачи", kb)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// askProof ask user to attach photo or document proving goal completion.
+// Proof is optional unless goal type requires it.
+func (t *Tasks) askProof(peerID int64, gType *goalTypes.GoalType) error {
+	message := fmt.Sprintf("%s\nПрикрепите фото или документ, подтверждающий выполнение",
+		gType.Name)
+	height := 1
+
+	if gType.RequiresProof {
+		message += "\nБез подтверждения задача не будет отмечена"
+		height = 0
+	}
+
+	kb := keyboard.NewKeyboard(keyboard.Config{
+		OneTime: true,
+		Inline:  false,
+		Width:   1,
+		Height:  height,
+	})
+
+	if !gType.RequiresProof {
+		kb.SetButton(0, 0, skipProofBtn)
+	}
+
+	kb.SetFooter(rejectBtn)
+	kbStr, err := kb.Marshal()
 
 	if err != nil {
 		return err
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   peerID,
-		"message":   "Задачи",
-		"random_id": 0,
-		"keyboard":  kb,
-	}, nil)
+	err = t.sender.SendKeyboard(peerID, message, kbStr)
 
 	if err != nil {
 		return err
@@ -439,11 +662,7 @@ func (t *Tasks) SendMain(ctx context.Context, peerID int64) error {
 }
 
 func (t *Tasks) NoGoals(peerID int64) error {
-	err := t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   peerID,
-		"message":   "Вы ничего не запланировали на этот день",
-		"random_id": 0,
-	}, nil)
+	err := t.sender.SendMessage(peerID, "Вы ничего не запланировали на этот день")
 
 	if err != nil {
 		return err
@@ -459,11 +678,7 @@ func (t *Tasks) SendGoal(ctx context.Context, peerID int64, uGoal *userGoals.Use
 		return err
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   peerID,
-		"message":   fmt.Sprintf("Текущая задача\n - %s", goal.Description),
-		"random_id": 0,
-	}, nil)
+	err = t.sender.SendMessage(peerID, fmt.Sprintf("Текущая задача\n - %s", goal.Description))
 
 	if err != nil {
 		return err
@@ -472,11 +687,12 @@ func (t *Tasks) SendGoal(ctx context.Context, peerID int64, uGoal *userGoals.Use
 	return nil
 }
 
-func (t *Tasks) SendGoalList(ctx context.Context, user *users.User, date time.Time) error {
+// GoalList render user goals of date.
+func (t *Tasks) GoalList(ctx context.Context, user *users.User, date time.Time) (string, error) {
 	uGoals, err := t.manager.UserGoals(ctx, user, date)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	uGoalsMap := make(map[int64]*userGoals.UserGoal)
@@ -488,7 +704,7 @@ func (t *Tasks) SendGoalList(ctx context.Context, user *users.User, date time.Ti
 	types, err := t.models.GoalTypes.List(ctx)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	var message string
@@ -500,7 +716,7 @@ func (t *Tasks) SendGoalList(ctx context.Context, user *users.User, date time.Ti
 			goal, err := t.models.Goals.Get(ctx, uGoal.GoalID)
 
 			if err != nil {
-				return err
+				return "", err
 			}
 
 			message += fmt.Sprintf("%s\n 💡 %s\n\n", gType.Name, goal.Description)
@@ -509,11 +725,18 @@ func (t *Tasks) SendGoalList(ctx context.Context, user *users.User, date time.Ti
 		}
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   message,
-		"random_id": 0,
-	}, nil)
+	return message, nil
+}
+
+// SendGoalList send user goals of date.
+func (t *Tasks) SendGoalList(ctx context.Context, user *users.User, date time.Time) error {
+	message, err := t.GoalList(ctx, user, date)
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendMessage(user.ID, message)
 
 	if err != nil {
 		return err
@@ -522,11 +745,18 @@ func (t *Tasks) SendGoalList(ctx context.Context, user *users.User, date time.Ti
 	return nil
 }
 
-func (t *Tasks) MarkGoalList(ctx context.Context, user *users.User, date time.Time) error {
+// MarkList render user goals of date with statuses and streaks.
+func (t *Tasks) MarkList(ctx context.Context, user *users.User, date time.Time) (string, error) {
 	uGoals, err := t.manager.UserGoals(ctx, user, date)
 
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	uDay, err := user.Day(date)
+
+	if err != nil {
+		return "", err
 	}
 
 	uGoalsMap := make(map[int64]*userGoals.UserGoal)
@@ -538,7 +768,7 @@ func (t *Tasks) MarkGoalList(ctx context.Context, user *users.User, date time.Ti
 	types, err := t.models.GoalTypes.List(ctx)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	var message string
@@ -550,7 +780,7 @@ func (t *Tasks) MarkGoalList(ctx context.Context, user *users.User, date time.Ti
 			goal, err := t.models.Goals.Get(ctx, uGoal.GoalID)
 
 			if err != nil {
-				return err
+				return "", err
 			}
 
 			var mark string
@@ -566,20 +796,40 @@ func (t *Tasks) MarkGoalList(ctx context.Context, user *users.User, date time.Ti
 				mark = "🍎 Првоалено"
 			}
 
-			message += fmt.Sprintf("%s\n 💡 %s\nСтатус - %s\n\n",
+			message += fmt.Sprintf("%s\n 💡 %s\nСтатус - %s\n",
 				gType.Name, goal.Description, mark)
+
+			streak, err := t.models.Streaks.Get(ctx, user.ID, gType.ID)
+
+			if err != nil && err != sql.ErrNoRows {
+				return "", err
+			}
+
+			if streak != nil && streak.Best > 0 {
+				message += fmt.Sprintf("Серия - 🔥 %d (лучшая %d)\n",
+					streak.Actual(*uDay), streak.Best)
+			}
+
+			message += "\n"
 		}
 	}
 
 	if message == "" {
-		return errNoGoals
+		return "", errNoGoals
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   message,
-		"random_id": 0,
-	}, nil)
+	return message, nil
+}
+
+// MarkGoalList send user goals of date with statuses and streaks.
+func (t *Tasks) MarkGoalList(ctx context.Context, user *users.User, date time.Time) error {
+	message, err := t.MarkList(ctx, user, date)
+
+	if err != nil {
+		return err
+	}
+
+	err = t.sender.SendMessage(user.ID, message)
 
 	if err != nil {
 		return err
@@ -625,11 +875,7 @@ func (t *Tasks) SendMarkList(ctx context.Context, user *users.User, date time.Ti
 		}
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   message,
-		"random_id": 0,
-	}, nil)
+	err = t.sender.SendMessage(user.ID, message)
 
 	if err != nil {
 		return err
@@ -639,7 +885,7 @@ func (t *Tasks) SendMarkList(ctx context.Context, user *users.User, date time.Ti
 }
 
 func (t *Tasks) choseDate(ctx context.Context, user *users.User, command string) error {
-	userTime, err := user.Date(time.Now())
+	today, err := user.Day(time.Now())
 
 	if err != nil {
 		return err
@@ -656,12 +902,17 @@ func (t *Tasks) choseDate(ctx context.Context, user *users.User, command string)
 
 	for i := 0; i < kb.Config.Height; i++ {
 		for j := 0; j < kb.Config.Width; j++ {
-			date := userTime.AddDate(0, 0, c+1)
+			day := today.AddDate(0, 0, c+1)
+			date, err := user.Midday(day)
+
+			if err != nil {
+				return err
+			}
 
 			btn := &keyboard.Button{
 				Color: "primary",
 				Action: keyboard.Action{
-					Label: fmt.Sprintf("%d %s", date.Day(), date.Month()),
+					Label: fmt.Sprintf("%d %s", day.Day(), day.Month()),
 					Type:  "text",
 					Payload: keyboard.Payload{
 						Command: command,
@@ -672,7 +923,7 @@ func (t *Tasks) choseDate(ctx context.Context, user *users.User, command string)
 				},
 			}
 
-			ok, err := t.manager.CheckDate(ctx, user, date)
+			ok, err := t.manager.CheckDate(ctx, user, *date)
 
 			if err != nil {
 				return err
@@ -694,12 +945,7 @@ func (t *Tasks) choseDate(ctx context.Context, user *users.User, command string)
 		return err
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   "Выберите дату",
-		"random_id": 0,
-		"keyboard":  kbStr,
-	}, nil)
+	err = t.sender.SendKeyboard(user.ID, "Выберите дату", kbStr)
 
 	if err != nil {
 		return err
@@ -771,12 +1017,7 @@ func (t *Tasks) markType(ctx context.Context, date time.Time, user *users.User)
 		return err
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   "Отметьте выполненные",
-		"random_id": 0,
-		"keyboard":  kbStr,
-	}, nil)
+	err = t.sender.SendKeyboard(user.ID, "Отметьте выполненные", kbStr)
 
 	if err != nil {
 		return err
@@ -840,12 +1081,7 @@ func (t *Tasks) choseType(ctx context.Context, date time.Time, user *users.User)
 		return err
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   user.ID,
-		"message":   "Выберите тип",
-		"random_id": 0,
-		"keyboard":  kbStr,
-	}, nil)
+	err = t.sender.SendKeyboard(user.ID, "Выберите тип", kbStr)
 
 	if err != nil {
 		return err
@@ -854,7 +1090,8 @@ func (t *Tasks) choseType(ctx context.Context, date time.Time, user *users.User)
 	return nil
 }
 
-func (t *Tasks) choseGoal(ctx context.Context, peerID int64, gType *goalTypes.GoalType) error {
+func (t *Tasks) choseGoal(ctx context.Context, peerID int64,
+	gType *goalTypes.GoalType, command string) error {
 	gls, err := t.models.Goals.List(ctx, gType.ID)
 
 	if err != nil {
@@ -890,7 +1127,7 @@ func (t *Tasks) choseGoal(ctx context.Context, peerID int64, gType *goalTypes.Go
 					Label: gls[c].Description,
 					Type:  "text",
 					Payload: keyboard.Payload{
-						Command: "chose_goal",
+						Command: command,
 						Params: map[string]interface{}{
 							"goal": gls[c].ID,
 						},
@@ -910,12 +1147,7 @@ func (t *Tasks) choseGoal(ctx context.Context, peerID int64, gType *goalTypes.Go
 		return err
 	}
 
-	err = t.vkClient.CallMethod("messages.send", vk.RequestParams{
-		"peer_id":   peerID,
-		"message":   "Выберите задачу",
-		"random_id": 0,
-		"keyboard":  kbStr,
-	}, nil)
+	err = t.sender.SendKeyboard(peerID, "Выберите задачу", kbStr)
 
 	if err != nil {
 		return err
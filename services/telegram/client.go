@@ -0,0 +1,343 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultURL default telegram bot api url.
+	DefaultURL = "https://api.telegram.org"
+
+	pollTimeout = 25
+)
+
+// Client telegram bot api client.
+type Client struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+// Error telegram bot api error.
+type Error struct {
+	Code        int
+	Description string
+}
+
+// Update telegram update.
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *ChatMessage   `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+// ChatMessage telegram chat message.
+type ChatMessage struct {
+	MessageID int64        `json:"message_id"`
+	From      *User        `json:"from"`
+	Chat      Chat         `json:"chat"`
+	Text      string       `json:"text"`
+	Caption   string       `json:"caption"`
+	Photo     []*PhotoSize `json:"photo"`
+	Document  *Document    `json:"document"`
+	Location  *Location    `json:"location"`
+}
+
+// PhotoSize telegram photo size, sizes go from smallest to biggest.
+type PhotoSize struct {
+	FileID string `json:"file_id"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Document telegram document.
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+}
+
+// Location telegram location.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// CallbackQuery telegram inline keyboard callback query.
+type CallbackQuery struct {
+	ID      string       `json:"id"`
+	From    User         `json:"from"`
+	Message *ChatMessage `json:"message"`
+	Data    string       `json:"data"`
+}
+
+// User telegram user.
+type User struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// Chat telegram chat.
+type Chat struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// InlineKeyboardMarkup telegram inline keyboard.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton telegram inline keyboard button.
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// NewClient create new instance of Client.
+func NewClient(url string, token string) *Client {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &Client{
+		url:   url,
+		token: token,
+		httpClient: &http.Client{
+			Timeout: (pollTimeout + 10) * time.Second,
+		},
+	}
+}
+
+// CallMethod call bot api method and decode result to response.
+func (c *Client) CallMethod(ctx context.Context, method string, params interface{}, response interface{}) error {
+	body, err := json.Marshal(params)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/bot%s/%s", c.url, c.token, method), bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, response)
+}
+
+// Upload call send method with file uploaded in field, e.g. sendPhoto or sendDocument.
+func (c *Client) Upload(ctx context.Context, method string, field string,
+	chatID int64, name string, data []byte, caption string) error {
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	err := writer.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+
+	if err != nil {
+		return err
+	}
+
+	err = writer.WriteField("caption", caption)
+
+	if err != nil {
+		return err
+	}
+
+	part, err := writer.CreateFormFile(field, name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write(data)
+
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/bot%s/%s", c.url, c.token, method), &body)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.do(req, nil)
+}
+
+// do send request and decode result to response.
+func (c *Client) do(req *http.Request, response interface{}) error {
+	resp, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+
+	if err != nil {
+		return err
+	}
+
+	if !result.Ok {
+		return &Error{
+			Code:        result.ErrorCode,
+			Description: result.Description,
+		}
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(result.Result, response)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMe get bot info.
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var user User
+
+	err := c.CallMethod(ctx, "getMe", struct{}{}, &user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetUpdates get updates with long polling.
+func (c *Client) GetUpdates(ctx context.Context, offset int64) ([]*Update, error) {
+	var updates []*Update
+
+	err := c.CallMethod(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         pollTimeout,
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// GetChat get chat info.
+func (c *Client) GetChat(ctx context.Context, chatID int64) (*Chat, error) {
+	var chat Chat
+
+	err := c.CallMethod(ctx, "getChat", map[string]interface{}{
+		"chat_id": chatID,
+	}, &chat)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &chat, nil
+}
+
+// SendMessage send message to chat.
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup) error {
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	}
+
+	if markup != nil {
+		params["reply_markup"] = markup
+	}
+
+	err := c.CallMethod(ctx, "sendMessage", params, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendPhoto send photo by file id to chat.
+func (c *Client) SendPhoto(ctx context.Context, chatID int64, fileID string, caption string) error {
+	err := c.CallMethod(ctx, "sendPhoto", map[string]interface{}{
+		"chat_id": chatID,
+		"photo":   fileID,
+		"caption": caption,
+	}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendDocument send document by file id to chat.
+func (c *Client) SendDocument(ctx context.Context, chatID int64, fileID string, caption string) error {
+	err := c.CallMethod(ctx, "sendDocument", map[string]interface{}{
+		"chat_id":  chatID,
+		"document": fileID,
+		"caption":  caption,
+	}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AnswerCallbackQuery answer to callback query.
+func (c *Client) AnswerCallbackQuery(ctx context.Context, queryID string) error {
+	err := c.CallMethod(ctx, "answerCallbackQuery", map[string]interface{}{
+		"callback_query_id": queryID,
+	}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Error get error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
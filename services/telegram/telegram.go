@@ -0,0 +1,451 @@
+package telegram
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/router"
+	"github.com/go-redis/redis/v8"
+)
+
+// service name of telegram service.
+const service = services.TransportTelegram
+
+const (
+	payloadTTL = 30 * 24 * time.Hour
+	retryDelay = 5 * time.Second
+)
+
+// Service wrapper for telegram bot api client.
+type Service struct {
+	client      *Client
+	redisClient *redis.Client
+	models      ModelsSet
+	router      *router.Router
+	pool        *pool.Pool
+	wg          sync.WaitGroup
+}
+
+// Config configuration for Service.
+type Config struct {
+	Token       string
+	URL         string
+	Models      ModelsSet
+	RedisClient *redis.Client
+	Pool        *pool.Pool
+}
+
+type ModelsSet struct {
+	Users users.Repository
+}
+
+// Message wrapper for telegram update.
+type Message struct {
+	*Update
+	payload *keyboard.Payload
+	user    *users.User
+}
+
+// NewService create new instance of Service.
+func NewService(config Config) *Service {
+	return &Service{
+		client:      NewClient(config.URL, config.Token),
+		redisClient: config.RedisClient,
+		models:      config.Models,
+		router:      router.NewRouter(),
+		pool:        config.Pool,
+	}
+}
+
+// Router get service router to register screen handlers.
+func (s *Service) Router() *router.Router {
+	return s.router
+}
+
+// Listen start long polling and handle updates until ctx is done.
+// Handlers get own context, so in-flight messages are finished on shutdown.
+func (s *Service) Listen(ctx context.Context) error {
+	_, err := s.client.GetMe(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		var offset int64
+
+		for ctx.Err() == nil {
+			updates, err := s.client.GetUpdates(ctx, offset)
+
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				log.Println(err)
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+				}
+
+				continue
+			}
+
+			for _, update := range updates {
+				offset = update.UpdateID + 1
+				message := &Message{Update: update}
+
+				if update.Message == nil && update.CallbackQuery == nil {
+					continue
+				}
+
+				err := s.pool.Submit(message.GetPeer(), func() {
+					err := s.handle(context.Background(), message)
+
+					if err != nil {
+						log.Println(err)
+					}
+				})
+
+				if err != nil {
+					log.Println(err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Wait wait listening to finish.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Service) handle(ctx context.Context, message *Message) error {
+	if message.CallbackQuery != nil {
+		err := s.client.AnswerCallbackQuery(ctx, message.CallbackQuery.ID)
+
+		if err != nil {
+			log.Println(err)
+		}
+
+		message.payload, err = s.getPayload(ctx, message.CallbackQuery.Data)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	user, err := s.models.Users.Get(ctx, message.GetPeer())
+
+	if err != nil {
+		return err
+	}
+
+	message.user = user
+	state := services.StateRegister
+
+	if user != nil {
+		state = user.State
+	}
+
+	err = s.router.Dispatch(ctx, state, message)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetUser get user info from telegram.
+func (s *Service) GetUser(userID int64) (*users.User, error) {
+	chat, err := s.client.GetChat(context.Background(), services.TelegramChatID(userID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &users.User{
+		ID:        userID,
+		FirstName: chat.FirstName,
+		LastName:  chat.LastName,
+		Active:    true,
+	}, nil
+}
+
+// SendMessage send message to user.
+func (s *Service) SendMessage(peerID int64, message string) error {
+	err := s.client.SendMessage(context.Background(), services.TelegramChatID(peerID),
+		message, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendKeyboard send message with keyboard to user.
+// Keyboard is expected in vk format, buttons are converted to
+// inline buttons with payloads stored behind callback data.
+func (s *Service) SendKeyboard(peerID int64, message string, kb string) error {
+	var vkKeyboard keyboard.Keyboard
+
+	err := json.Unmarshal([]byte(kb), &vkKeyboard)
+
+	if err != nil {
+		return err
+	}
+
+	markup := &InlineKeyboardMarkup{}
+
+	for _, buttons := range vkKeyboard.Buttons {
+		var row []InlineKeyboardButton
+
+		for _, button := range buttons {
+			if button == nil {
+				continue
+			}
+
+			data, err := s.setPayload(context.Background(), button.Action.PayloadStr)
+
+			if err != nil {
+				return err
+			}
+
+			row = append(row, InlineKeyboardButton{
+				Text:         button.Action.Label,
+				CallbackData: data,
+			})
+		}
+
+		if len(row) > 0 {
+			markup.InlineKeyboard = append(markup.InlineKeyboard, row)
+		}
+	}
+
+	if len(markup.InlineKeyboard) == 0 {
+		markup = nil
+	}
+
+	err = s.client.SendMessage(context.Background(), services.TelegramChatID(peerID),
+		message, markup)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendList send message with list to user.
+func (s *Service) SendList(peerID int64, message string, list []services.ListItem) error {
+	for _, item := range list {
+		message += fmt.Sprintf("\n - %s", item.GetLabel())
+	}
+
+	return s.SendMessage(peerID, message)
+}
+
+// SendAttachments send message with attachments to user.
+// Message is used as caption of the first attachment.
+func (s *Service) SendAttachments(peerID int64, message string, attachments []services.Attachment) error {
+	ctx := context.Background()
+	chatID := services.TelegramChatID(peerID)
+	sent := false
+
+	for _, attachment := range attachments {
+		if attachment.Service != service {
+			continue
+		}
+
+		var err error
+
+		switch attachment.Type {
+		case services.AttachmentPhoto:
+			err = s.client.SendPhoto(ctx, chatID, attachment.ID, message)
+		case services.AttachmentDoc:
+			err = s.client.SendDocument(ctx, chatID, attachment.ID, message)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		sent = true
+		message = ""
+	}
+
+	if sent {
+		return nil
+	}
+
+	return s.SendMessage(peerID, message)
+}
+
+// SendPhoto upload PNG photo and send it with message to user.
+func (s *Service) SendPhoto(peerID int64, message string, photo []byte) error {
+	err := s.client.Upload(context.Background(), "sendPhoto", "photo",
+		services.TelegramChatID(peerID), "photo.png", photo, message)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendDocument upload file and send it with message to user.
+func (s *Service) SendDocument(peerID int64, message string, name string, data []byte) error {
+	err := s.client.Upload(context.Background(), "sendDocument", "document",
+		services.TelegramChatID(peerID), name, data, message)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setPayload store payload and return callback data for it.
+// Callback data is limited to 64 bytes, so payload itself is kept in redis.
+func (s *Service) setPayload(ctx context.Context, payload string) (string, error) {
+	hash := sha1.Sum([]byte(payload))
+	data := hex.EncodeToString(hash[:])
+
+	err := s.redisClient.Set(ctx, s.payloadKey(data), payload, payloadTTL).Err()
+
+	if err != nil {
+		return "", err
+	}
+
+	return data, nil
+}
+
+func (s *Service) getPayload(ctx context.Context, data string) (*keyboard.Payload, error) {
+	raw, err := s.redisClient.Get(ctx, s.payloadKey(data)).Result()
+
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var payload keyboard.Payload
+
+	err = json.Unmarshal([]byte(raw), &payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+func (s *Service) payloadKey(data string) string {
+	return fmt.Sprintf("telegram_payload_%s", data)
+}
+
+// GetPeer get message peer, it is user ID of chat.
+func (m *Message) GetPeer() int64 {
+	return services.TelegramUserID(m.chatID())
+}
+
+func (m *Message) chatID() int64 {
+	if m.CallbackQuery != nil {
+		if m.CallbackQuery.Message != nil {
+			return m.CallbackQuery.Message.Chat.ID
+		}
+
+		return m.CallbackQuery.From.ID
+	}
+
+	return m.Message.Chat.ID
+}
+
+// GetText get message text.
+func (m *Message) GetText() string {
+	if m.Message == nil {
+		return ""
+	}
+
+	if m.Message.Text == "" {
+		return m.Message.Caption
+	}
+
+	return m.Message.Text
+}
+
+// GetPayload get message payload.
+func (m *Message) GetPayload() (services.Payload, error) {
+	if m.payload == nil {
+		return nil, nil
+	}
+
+	return m.payload, nil
+}
+
+// GetUser get message user.
+func (m *Message) GetUser() *users.User {
+	return m.user
+}
+
+// GetAttachments get message photos and documents.
+func (m *Message) GetAttachments() []services.Attachment {
+	if m.Message == nil {
+		return nil
+	}
+
+	var attachments []services.Attachment
+
+	if len(m.Message.Photo) > 0 {
+		attachments = append(attachments, services.Attachment{
+			Service: service,
+			Type:    services.AttachmentPhoto,
+			ID:      m.Message.Photo[len(m.Message.Photo)-1].FileID,
+		})
+	}
+
+	if m.Message.Document != nil {
+		attachments = append(attachments, services.Attachment{
+			Service: service,
+			Type:    services.AttachmentDoc,
+			ID:      m.Message.Document.FileID,
+		})
+	}
+
+	return attachments
+}
+
+// GetLocation get message location, nil if it has no location.
+func (m *Message) GetLocation() *services.Location {
+	if m.Message == nil || m.Message.Location == nil {
+		return nil
+	}
+
+	return &services.Location{
+		Latitude:  m.Message.Location.Latitude,
+		Longitude: m.Message.Location.Longitude,
+	}
+}
@@ -0,0 +1,253 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/router"
+)
+
+const testToken = "token"
+
+// botAPI stub of telegram bot api, which records calls
+// and answers them by method handlers.
+type botAPI struct {
+	mu      sync.Mutex
+	calls   map[string][]map[string]interface{}
+	methods map[string]func(params map[string]interface{}) interface{}
+}
+
+func newBotAPI(t *testing.T) (*botAPI, *httptest.Server) {
+	api := &botAPI{
+		calls:   make(map[string][]map[string]interface{}),
+		methods: make(map[string]func(params map[string]interface{}) interface{}),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(server.Close)
+
+	return api, server
+}
+
+func (a *botAPI) serve(w http.ResponseWriter, r *http.Request) {
+	prefix := "/bot" + testToken + "/"
+
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+
+	method := strings.TrimPrefix(r.URL.Path, prefix)
+	params := make(map[string]interface{})
+	_ = json.NewDecoder(r.Body).Decode(&params)
+
+	a.mu.Lock()
+	a.calls[method] = append(a.calls[method], params)
+	handler, ok := a.methods[method]
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !ok {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"ok":          false,
+			"error_code":  404,
+			"description": "Not Found: method not found",
+		})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":     true,
+		"result": handler(params),
+	})
+}
+
+func (a *botAPI) handle(method string, handler func(params map[string]interface{}) interface{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.methods[method] = handler
+}
+
+func (a *botAPI) called(method string) []map[string]interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.calls[method]
+}
+
+func TestListen(t *testing.T) {
+	api, server := newBotAPI(t)
+
+	api.handle("getMe", func(map[string]interface{}) interface{} {
+		return User{ID: 1, FirstName: "oracle"}
+	})
+
+	api.handle("getUpdates", func(params map[string]interface{}) interface{} {
+		if params["offset"].(float64) > 0 {
+			time.Sleep(10 * time.Millisecond)
+			return []interface{}{}
+		}
+
+		return []Update{{
+			UpdateID: 7,
+			Message: &ChatMessage{
+				MessageID: 1,
+				Chat:      Chat{ID: 42},
+				Text:      "привет",
+			},
+		}}
+	})
+
+	workers := pool.NewPool(pool.Config{Size: 1})
+	defer workers.Stop()
+
+	s := NewService(Config{
+		Token: testToken,
+		URL:   server.URL,
+		Pool:  workers,
+		Models: ModelsSet{
+			Users: users.NewMemory(),
+		},
+	})
+
+	received := make(chan services.Message, 1)
+
+	s.Router().Register(services.StateRegister, router.HandlerFunc(
+		func(_ context.Context, message services.Message) (string, error) {
+			received <- message
+			return "", nil
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := s.Listen(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case message := <-received:
+		if message.GetPeer() != services.TelegramUserID(42) {
+			t.Errorf("peer = %d, want %d", message.GetPeer(), services.TelegramUserID(42))
+		}
+
+		if message.GetText() != "привет" {
+			t.Errorf("text = %q, want %q", message.GetText(), "привет")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("update was not dispatched")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for len(api.called("getUpdates")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	s.Wait()
+
+	polls := api.called("getUpdates")
+
+	if len(polls) < 2 {
+		t.Fatalf("getUpdates called %d times, want at least 2", len(polls))
+	}
+
+	if polls[1]["offset"].(float64) != 8 {
+		t.Errorf("offset = %v, want 8", polls[1]["offset"])
+	}
+}
+
+func TestListenGetMeError(t *testing.T) {
+	_, server := newBotAPI(t)
+
+	s := NewService(Config{
+		Token: testToken,
+		URL:   server.URL,
+	})
+
+	err := s.Listen(context.Background())
+
+	var apiErr *Error
+
+	if !errors.As(err, &apiErr) || apiErr.Code != 404 {
+		t.Fatalf("err = %v, want bot api 404 error", err)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	api, server := newBotAPI(t)
+
+	api.handle("sendMessage", func(map[string]interface{}) interface{} {
+		return ChatMessage{MessageID: 1}
+	})
+
+	s := NewService(Config{
+		Token: testToken,
+		URL:   server.URL,
+	})
+
+	err := s.SendMessage(services.TelegramUserID(42), "текст")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := api.called("sendMessage")
+
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage called %d times, want 1", len(calls))
+	}
+
+	if calls[0]["chat_id"].(float64) != 42 {
+		t.Errorf("chat_id = %v, want 42", calls[0]["chat_id"])
+	}
+
+	if calls[0]["text"] != "текст" {
+		t.Errorf("text = %v, want %q", calls[0]["text"], "текст")
+	}
+
+	if _, ok := calls[0]["reply_markup"]; ok {
+		t.Error("reply_markup is sent without keyboard")
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	api, server := newBotAPI(t)
+
+	api.handle("getChat", func(map[string]interface{}) interface{} {
+		return Chat{ID: 42, FirstName: "Иван", LastName: "Петров"}
+	})
+
+	s := NewService(Config{
+		Token: testToken,
+		URL:   server.URL,
+	})
+
+	user, err := s.GetUser(services.TelegramUserID(42))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.ID != services.TelegramUserID(42) || user.FirstName != "Иван" || user.LastName != "Петров" {
+		t.Errorf("user = %+v", user)
+	}
+
+	if api.called("getChat")[0]["chat_id"].(float64) != 42 {
+		t.Errorf("chat_id = %v, want 42", api.called("getChat")[0]["chat_id"])
+	}
+}
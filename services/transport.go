@@ -0,0 +1,31 @@
+package services
+
+// Transport names.
+const (
+	TransportVK       = "vk"
+	TransportTelegram = "telegram"
+)
+
+// telegramOffset shift of telegram user IDs. Users of all transports
+// share users table, so telegram chat IDs, which have at most 52
+// significant bits, are moved above VK IDs to never meet them.
+const telegramOffset int64 = 1 << 53
+
+// TelegramUserID get user ID of telegram chat.
+func TelegramUserID(chatID int64) int64 {
+	return telegramOffset + chatID
+}
+
+// TelegramChatID get telegram chat of user ID.
+func TelegramChatID(userID int64) int64 {
+	return userID - telegramOffset
+}
+
+// Transport get name of transport which owns user ID.
+func Transport(userID int64) string {
+	if userID > telegramOffset>>1 {
+		return TransportTelegram
+	}
+
+	return TransportVK
+}
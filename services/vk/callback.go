@@ -0,0 +1,370 @@
+package vk
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Zetkolink/oracle/services"
+	vkSDK "github.com/go-vk-api/vk"
+	lp "github.com/go-vk-api/vk/longpoll/user"
+)
+
+// Inbound modes.
+const (
+	ModeLongPoll = "longpoll"
+	ModeCallback = "callback"
+)
+
+const (
+	defaultCallbackAddr = ":8081"
+	defaultCallbackPath = "/vk/callback"
+	maxEventSize        = 1 << 20
+	callbackTimeout     = 10 * time.Second
+)
+
+var (
+	// ErrNoSecret callback secret key not configured.
+	ErrNoSecret = errors.New("vk callback secret required")
+
+	errUnknownMode = errors.New("unknown vk mode")
+)
+
+// CallbackConfig configuration of Callback API server. Events can
+// be replayed locally from recorded payloads in testdata/callback:
+//
+//	curl -d @testdata/callback/message_new.json localhost:8081/vk/callback
+type CallbackConfig struct {
+	Addr string
+	Path string
+	// Confirmation string returned to confirm server address.
+	Confirmation string
+	// Secret secret key sent by VK with every event.
+	Secret string
+	// GroupID community ID, events of other groups are rejected.
+	GroupID int64
+}
+
+// callbackEvent Callback API event.
+type callbackEvent struct {
+	Type    string          `json:"type"`
+	GroupID int64           `json:"group_id"`
+	EventID string          `json:"event_id"`
+	Secret  string          `json:"secret"`
+	Object  json.RawMessage `json:"object"`
+}
+
+// callbackMessage message_new event object.
+type callbackMessage struct {
+	Message struct {
+		ID          int64                `json:"id"`
+		Date        int64                `json:"date"`
+		PeerID      int64                `json:"peer_id"`
+		Text        string               `json:"text"`
+		Payload     string               `json:"payload"`
+		Attachments []callbackAttachment `json:"attachments"`
+		Geo         *struct {
+			Coordinates services.Location `json:"coordinates"`
+		} `json:"geo"`
+	} `json:"message"`
+}
+
+// callbackAttachment message attachment, only photos
+// and documents are passed to screens.
+type callbackAttachment struct {
+	Type  string        `json:"type"`
+	Photo *callbackFile `json:"photo"`
+	Doc   *callbackFile `json:"doc"`
+}
+
+type callbackFile struct {
+	ID        int64  `json:"id"`
+	OwnerID   int64  `json:"owner_id"`
+	AccessKey string `json:"access_key"`
+}
+
+// callbackButton message_event event object, sent on callback button press.
+type callbackButton struct {
+	UserID  int64           `json:"user_id"`
+	PeerID  int64           `json:"peer_id"`
+	EventID string          `json:"event_id"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Mode get inbound mode.
+func (s *Service) Mode() string {
+	return s.mode
+}
+
+// listenCallback start Callback API server until ctx is done.
+func (s *Service) listenCallback(ctx context.Context) error {
+	if s.callback.Secret == "" {
+		return ErrNoSecret
+	}
+
+	if s.callback.Addr == "" {
+		s.callback.Addr = defaultCallbackAddr
+	}
+
+	if s.callback.Path == "" {
+		s.callback.Path = defaultCallbackPath
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.callback.Path, s.handleCallback)
+
+	server := &http.Server{
+		Addr:         s.callback.Addr,
+		Handler:      mux,
+		ReadTimeout:  callbackTimeout,
+		WriteTimeout: callbackTimeout,
+	}
+
+	listener, err := net.Listen("tcp", server.Addr)
+
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(2)
+
+	go func() {
+		defer s.wg.Done()
+
+		err := server.Serve(listener)
+
+		if err != nil && err != http.ErrServerClosed {
+			log.Println(err)
+		}
+	}()
+
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+		defer cancel()
+
+		err := server.Shutdown(shutdownCtx)
+
+		if err != nil {
+			log.Println(err)
+		}
+	}()
+
+	return nil
+}
+
+// handleCallback handle Callback API event. VK retries event
+// until it gets "ok", so messages are handled asynchronously
+// and retried events are handled once by event ID.
+func (s *Service) handleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var event callbackEvent
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEventSize)).Decode(&event)
+
+	if err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(event.Secret), []byte(s.callback.Secret)) != 1 ||
+		(s.callback.GroupID != 0 && event.GroupID != s.callback.GroupID) {
+
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch event.Type {
+	case "confirmation":
+		_, err = w.Write([]byte(s.callback.Confirmation))
+
+		if err != nil {
+			log.Println(err)
+		}
+
+		return
+	case "message_new", "message_event":
+	default:
+		s.answerOK(w)
+		return
+	}
+
+	var message *Message
+
+	if event.Type == "message_new" {
+		message, err = s.callbackMessage(event.Object)
+	} else {
+		message, err = s.callbackButton(event.Object)
+	}
+
+	// Malformed event is not fixed by retry.
+	if err != nil {
+		log.Println(err)
+		s.answerOK(w)
+		return
+	}
+
+	if event.EventID != "" {
+		ok, err := s.models.Events.Claim(r.Context(), service, event.EventID)
+
+		if err != nil {
+			log.Println(err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		// Event is retried after it was handled, answer
+		// "ok" again to stop retries.
+		if !ok {
+			s.answerOK(w)
+			return
+		}
+	}
+
+	err = s.submit(message)
+
+	// Event is released, so it is handled when VK retries it.
+	if err != nil {
+		log.Println(err)
+
+		if event.EventID != "" {
+			err = s.models.Events.Release(r.Context(), service, event.EventID)
+
+			if err != nil {
+				log.Println(err)
+			}
+		}
+
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	s.answerOK(w)
+}
+
+// answerOK confirm event is received.
+func (s *Service) answerOK(w http.ResponseWriter) {
+	_, err := w.Write([]byte("ok"))
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// callbackMessage convert message to long poll format.
+func (s *Service) callbackMessage(object json.RawMessage) (*Message, error) {
+	var event callbackMessage
+
+	err := json.Unmarshal(object, &event)
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw := event.Message
+	msg := &lp.NewMessage{
+		ID:          raw.ID,
+		PeerID:      raw.PeerID,
+		Timestamp:   raw.Date,
+		Text:        raw.Text,
+		Attachments: make(map[string]string),
+	}
+
+	if raw.Payload != "" {
+		msg.Attachments["payload"] = raw.Payload
+	}
+
+	i := 0
+
+	for _, attachment := range raw.Attachments {
+		file := attachment.Photo
+
+		if attachment.Type == services.AttachmentDoc {
+			file = attachment.Doc
+		}
+
+		if file == nil {
+			continue
+		}
+
+		i++
+		msg.Attachments[fmt.Sprintf("attach%d_type", i)] = attachment.Type
+		msg.Attachments[fmt.Sprintf("attach%d", i)] = file.id()
+	}
+
+	message := &Message{
+		NewMessage: msg,
+	}
+
+	if raw.Geo != nil {
+		location := raw.Geo.Coordinates
+		message.location = &location
+	}
+
+	return message, nil
+}
+
+// callbackButton answer callback button press and convert its payload.
+func (s *Service) callbackButton(object json.RawMessage) (*Message, error) {
+	var event callbackButton
+
+	err := json.Unmarshal(object, &event)
+
+	if err != nil {
+		return nil, err
+	}
+
+	message := &Message{
+		NewMessage: &lp.NewMessage{
+			PeerID:    event.PeerID,
+			Timestamp: time.Now().Unix(),
+			Attachments: map[string]string{
+				"payload": string(event.Payload),
+			},
+		},
+	}
+
+	err = s.CallMethod("messages.sendMessageEventAnswer", vkSDK.RequestParams{
+		"event_id": event.EventID,
+		"user_id":  event.UserID,
+		"peer_id":  event.PeerID,
+	}, nil)
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	return message, nil
+}
+
+// submit handle message in peer queue.
+func (s *Service) submit(message *Message) error {
+	return s.pool.Submit(message.PeerID, func() {
+		err := s.dispatch(context.Background(), message)
+
+		if err != nil {
+			log.Println(err)
+		}
+	})
+}
+
+// id get attachment ID as in long poll, with access key if any.
+func (f *callbackFile) id() string {
+	if f.AccessKey != "" {
+		return fmt.Sprintf("%d_%d_%s", f.OwnerID, f.ID, f.AccessKey)
+	}
+
+	return fmt.Sprintf("%d_%d", f.OwnerID, f.ID)
+}
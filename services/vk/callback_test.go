@@ -0,0 +1,311 @@
+package vk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/Zetkolink/oracle/models/events"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/router"
+)
+
+const (
+	testSecret       = "secret"
+	testConfirmation = "a1b2c3"
+)
+
+// callbackTest callback service, which records dispatched messages.
+type callbackTest struct {
+	service *Service
+	workers *pool.Pool
+
+	mu       sync.Mutex
+	messages []services.Message
+}
+
+func newCallbackTest(t *testing.T) *callbackTest {
+	c := &callbackTest{
+		workers: pool.NewPool(pool.Config{Size: 1}),
+	}
+
+	c.service = NewService(Config{
+		Pool: c.workers,
+		Mode: ModeCallback,
+		Callback: CallbackConfig{
+			Confirmation: testConfirmation,
+			Secret:       testSecret,
+			GroupID:      1,
+		},
+		Models: ModelsSet{
+			Users:  users.NewMemory(),
+			Events: events.NewMemory(),
+		},
+	})
+
+	c.service.Router().Register(services.StateRegister, router.HandlerFunc(
+		func(_ context.Context, message services.Message) (string, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			c.messages = append(c.messages, message)
+
+			return "", nil
+		}))
+
+	return c
+}
+
+// post send recorded event, fields are replaced by patch.
+func (c *callbackTest) post(t *testing.T, name string,
+	patch map[string]interface{}) *httptest.ResponseRecorder {
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "callback", name+".json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patch != nil {
+		event := make(map[string]interface{})
+
+		err = json.Unmarshal(data, &event)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for field, value := range patch {
+			event[field] = value
+		}
+
+		data, err = json.Marshal(event)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, defaultCallbackPath, bytes.NewReader(data))
+	w := httptest.NewRecorder()
+
+	c.service.handleCallback(w, r)
+
+	return w
+}
+
+// dispatched wait submitted messages and get dispatched ones.
+func (c *callbackTest) dispatched() []services.Message {
+	c.workers.Stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.messages
+}
+
+func TestCallbackConfirmation(t *testing.T) {
+	c := newCallbackTest(t)
+
+	w := c.post(t, "confirmation", nil)
+
+	if w.Code != http.StatusOK || w.Body.String() != testConfirmation {
+		t.Errorf("response = %d %q, want %d %q",
+			w.Code, w.Body.String(), http.StatusOK, testConfirmation)
+	}
+
+	if len(c.dispatched()) != 0 {
+		t.Error("confirmation is dispatched")
+	}
+}
+
+func TestCallbackRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		patch map[string]interface{}
+	}{
+		{name: "confirmation secret mismatch", event: "confirmation",
+			patch: map[string]interface{}{"secret": "wrong"}},
+		{name: "message secret mismatch", event: "message_new",
+			patch: map[string]interface{}{"secret": "wrong"}},
+		{name: "message without secret", event: "message_new",
+			patch: map[string]interface{}{"secret": ""}},
+		{name: "other group", event: "message_new",
+			patch: map[string]interface{}{"group_id": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCallbackTest(t)
+
+			w := c.post(t, tt.event, tt.patch)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+
+			if w.Body.String() == testConfirmation || w.Body.String() == "ok" {
+				t.Errorf("body = %q for rejected event", w.Body.String())
+			}
+
+			if len(c.dispatched()) != 0 {
+				t.Error("rejected event is dispatched")
+			}
+		})
+	}
+}
+
+func TestCallbackMessageNew(t *testing.T) {
+	tests := []struct {
+		event       string
+		text        string
+		payload     string
+		attachments []services.Attachment
+		location    *services.Location
+	}{
+		{event: "message_new", text: "Меню", payload: "menu"},
+		{event: "message_new_photo", attachments: []services.Attachment{{
+			Service: service,
+			Type:    services.AttachmentPhoto,
+			ID:      "100_457239017_f3a2b1c0d9e8f7a6b5",
+		}}},
+		{event: "message_new_geo",
+			location: &services.Location{Latitude: 56.838011, Longitude: 60.597474}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			c := newCallbackTest(t)
+
+			w := c.post(t, tt.event, nil)
+
+			if w.Code != http.StatusOK || w.Body.String() != "ok" {
+				t.Errorf("response = %d %q, want %d %q", w.Code, w.Body.String(), http.StatusOK, "ok")
+			}
+
+			messages := c.dispatched()
+
+			if len(messages) != 1 {
+				t.Fatalf("dispatched %d messages, want 1", len(messages))
+			}
+
+			message := messages[0]
+
+			if message.GetPeer() != 100 {
+				t.Errorf("peer = %d, want 100", message.GetPeer())
+			}
+
+			if message.GetText() != tt.text {
+				t.Errorf("text = %q, want %q", message.GetText(), tt.text)
+			}
+
+			payload, err := message.GetPayload()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			command := ""
+
+			if payload != nil {
+				command = payload.GetCommand()
+			}
+
+			if command != tt.payload {
+				t.Errorf("command = %q, want %q", command, tt.payload)
+			}
+
+			attachments := message.GetAttachments()
+
+			if len(attachments) != len(tt.attachments) {
+				t.Fatalf("attachments = %+v, want %+v", attachments, tt.attachments)
+			}
+
+			for i := range attachments {
+				if attachments[i] != tt.attachments[i] {
+					t.Errorf("attachment = %+v, want %+v", attachments[i], tt.attachments[i])
+				}
+			}
+
+			location := message.GetLocation()
+
+			if (location == nil) != (tt.location == nil) ||
+				(location != nil && *location != *tt.location) {
+
+				t.Errorf("location = %v, want %v", location, tt.location)
+			}
+		})
+	}
+}
+
+// VK retries event until it gets "ok", retried
+// events must be answered but not handled again.
+func TestCallbackRetried(t *testing.T) {
+	c := newCallbackTest(t)
+
+	for i := 0; i < 3; i++ {
+		w := c.post(t, "message_new", nil)
+
+		if w.Code != http.StatusOK || w.Body.String() != "ok" {
+			t.Errorf("attempt %d: response = %d %q, want %d %q",
+				i+1, w.Code, w.Body.String(), http.StatusOK, "ok")
+		}
+	}
+
+	// Other event of same message is handled.
+	w := c.post(t, "message_new", map[string]interface{}{"event_id": "other"})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if messages := c.dispatched(); len(messages) != 2 {
+		t.Errorf("dispatched %d messages, want 2", len(messages))
+	}
+}
+
+func TestCallbackConfirmationRetried(t *testing.T) {
+	c := newCallbackTest(t)
+
+	for i := 0; i < 2; i++ {
+		w := c.post(t, "confirmation", map[string]interface{}{"event_id": "confirm"})
+
+		if w.Body.String() != testConfirmation {
+			t.Errorf("attempt %d: body = %q, want %q", i+1, w.Body.String(), testConfirmation)
+		}
+	}
+}
+
+// Event which is not submitted must be handled on retry.
+func TestCallbackSubmitFailed(t *testing.T) {
+	c := newCallbackTest(t)
+	c.workers.Stop()
+
+	w := c.post(t, "message_new", nil)
+
+	if w.Code < http.StatusInternalServerError {
+		t.Errorf("status = %d, want 5xx", w.Code)
+	}
+
+	c.workers = pool.NewPool(pool.Config{Size: 1})
+	c.service.pool = c.workers
+
+	w = c.post(t, "message_new", nil)
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("retry response = %d %q, want %d %q", w.Code, w.Body.String(), http.StatusOK, "ok")
+	}
+
+	if messages := c.dispatched(); len(messages) != 1 {
+		t.Errorf("dispatched %d messages, want 1", len(messages))
+	}
+}
@@ -0,0 +1,165 @@
+package vk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	vkSDK "github.com/go-vk-api/vk"
+)
+
+// uploadPhoto upload PNG photo for messages to peer and
+// return attachment id.
+func (s *Service) uploadPhoto(peerID int64, photo []byte) (string, error) {
+	var server struct {
+		UploadURL string `json:"upload_url"`
+	}
+
+	err := s.CallMethod("photos.getMessagesUploadServer", vkSDK.RequestParams{
+		"peer_id": peerID,
+	}, &server)
+
+	if err != nil {
+		return "", err
+	}
+
+	var uploaded struct {
+		Server int64  `json:"server"`
+		Photo  string `json:"photo"`
+		Hash   string `json:"hash"`
+	}
+
+	err = s.uploadFile(server.UploadURL, "photo", "photo.png", photo, &uploaded)
+
+	if err != nil {
+		return "", err
+	}
+
+	if uploaded.Photo == "" || uploaded.Photo == "[]" {
+		return "", errors.New("photo not uploaded")
+	}
+
+	var saved []struct {
+		ID      int64 `json:"id"`
+		OwnerID int64 `json:"owner_id"`
+	}
+
+	err = s.CallMethod("photos.saveMessagesPhoto", vkSDK.RequestParams{
+		"server": uploaded.Server,
+		"photo":  uploaded.Photo,
+		"hash":   uploaded.Hash,
+	}, &saved)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(saved) == 0 {
+		return "", errors.New("photo not saved")
+	}
+
+	return fmt.Sprintf("%d_%d", saved[0].OwnerID, saved[0].ID), nil
+}
+
+// uploadDocument upload document for messages to peer and
+// return attachment id.
+func (s *Service) uploadDocument(peerID int64, name string, data []byte) (string, error) {
+	var server struct {
+		UploadURL string `json:"upload_url"`
+	}
+
+	err := s.CallMethod("docs.getMessagesUploadServer", vkSDK.RequestParams{
+		"type":    "doc",
+		"peer_id": peerID,
+	}, &server)
+
+	if err != nil {
+		return "", err
+	}
+
+	var uploaded struct {
+		File  string `json:"file"`
+		Error string `json:"error"`
+	}
+
+	err = s.uploadFile(server.UploadURL, "file", name, data, &uploaded)
+
+	if err != nil {
+		return "", err
+	}
+
+	if uploaded.File == "" {
+		return "", fmt.Errorf("document not uploaded: %s", uploaded.Error)
+	}
+
+	var saved struct {
+		Doc struct {
+			ID      int64 `json:"id"`
+			OwnerID int64 `json:"owner_id"`
+		} `json:"doc"`
+	}
+
+	err = s.CallMethod("docs.save", vkSDK.RequestParams{
+		"file":  uploaded.File,
+		"title": name,
+	}, &saved)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d_%d", saved.Doc.OwnerID, saved.Doc.ID), nil
+}
+
+// uploadFile post file to upload server and decode response.
+func (s *Service) uploadFile(url string, field string, name string,
+	data []byte, response interface{}) error {
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile(field, name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write(data)
+
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := s.HTTPClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(response)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
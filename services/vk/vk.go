@@ -3,141 +3,120 @@ package vk
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
-	"time"
+	"strings"
+	"sync"
 
-	"github.com/Zetkolink/oracle/manager"
-	"github.com/Zetkolink/oracle/models/goalTypes"
-	"github.com/Zetkolink/oracle/models/goals"
+	"github.com/Zetkolink/oracle/models/events"
 	"github.com/Zetkolink/oracle/models/keyboards"
-	"github.com/Zetkolink/oracle/models/userGoals"
 	"github.com/Zetkolink/oracle/models/users"
-	"github.com/Zetkolink/oracle/models/whiteList"
-	"github.com/Zetkolink/oracle/notificator"
-	"github.com/Zetkolink/oracle/rater"
-	s "github.com/Zetkolink/oracle/services"
-	"github.com/Zetkolink/oracle/services/vk/appraiser"
-	"github.com/Zetkolink/oracle/services/vk/keyboard"
-	"github.com/Zetkolink/oracle/services/vk/menu"
-	"github.com/Zetkolink/oracle/services/vk/registrar"
-	"github.com/Zetkolink/oracle/services/vk/tasks"
-	"github.com/go-redis/redis/v8"
+	"github.com/Zetkolink/oracle/services"
+	"github.com/Zetkolink/oracle/services/keyboard"
+	"github.com/Zetkolink/oracle/services/pool"
+	"github.com/Zetkolink/oracle/services/router"
 	vkSDK "github.com/go-vk-api/vk"
 	lp "github.com/go-vk-api/vk/longpoll/user"
-	"googlemaps.github.io/maps"
 )
 
+// service name of vk service.
+const service = services.TransportVK
+
 // Service wrapper for vk api client.
 type Service struct {
 	*vkSDK.Client
-	redisClient *redis.Client
-	models      ModelsSet
-	mapsClient  *maps.Client
-	registrar   *registrar.Registrar
-	menu        *menu.Menu
-	tasks       *tasks.Tasks
-	appraiser   *appraiser.Appraiser
-	notificator *notificator.Notificator
+	models   ModelsSet
+	router   *router.Router
+	pool     *pool.Pool
+	mode     string
+	callback CallbackConfig
+	wg       sync.WaitGroup
 }
 
 // Config configuration for Service.
 type Config struct {
-	Models      ModelsSet
-	VKClient    *vkSDK.Client
-	RedisClient *redis.Client
-	MapsClient  *maps.Client
-	Manager     *manager.Manager
-	Rater       *rater.Rater
-	Notificator *notificator.Notificator
+	Models   ModelsSet
+	VKClient *vkSDK.Client
+	Pool     *pool.Pool
+	// Mode inbound mode, long poll by default.
+	Mode     string
+	Callback CallbackConfig
 }
 
 type ModelsSet struct {
-	Users     *users.Model
-	WhiteList *whiteList.Model
-	Keyboards *keyboards.Model
-	GoalTypes *goalTypes.Model
-	Goals     *goals.Model
-	UserGoals *userGoals.Model
+	Users     users.Repository
+	Keyboards keyboards.Repository
+	// Events handled callback events, retried ones are skipped.
+	Events events.Repository
 }
 
 // Message wrapper for vk new message.
 type Message struct {
 	*lp.NewMessage
-	user *users.User
+	user     *users.User
+	location *services.Location
 }
 
 // NewService create new instance of Service.
 func NewService(config Config) *Service {
-	r := registrar.NewRegistrar(registrar.Config{
-		VKClient:   config.VKClient,
-		MapsClient: config.MapsClient,
-		Models: registrar.ModelsSet{
-			WhiteList: config.Models.WhiteList,
-			Users:     config.Models.Users,
-			Keyboards: config.Models.Keyboards,
-		},
-	})
-
-	m := menu.NewMenu(menu.Config{
-		VKClient: config.VKClient,
-		Models: menu.ModelsSet{
-			WhiteList: config.Models.WhiteList,
-			Users:     config.Models.Users,
-			Keyboards: config.Models.Keyboards,
-		},
-	})
-
-	t := tasks.NewTasks(tasks.Config{
-		VKClient: config.VKClient,
-		Models: tasks.ModelsSet{
-			Users:     config.Models.Users,
-			Keyboards: config.Models.Keyboards,
-			GoalTypes: config.Models.GoalTypes,
-			Goals:     config.Models.Goals,
-		},
-		Manager:     config.Manager,
-		RedisClient: config.RedisClient,
-	})
-
-	a := appraiser.NewAppraiser(appraiser.Config{
-		VKClient: config.VKClient,
-		Rater:    config.Rater,
-		Models: appraiser.ModelsSet{
-			Users:     config.Models.Users,
-			UserGoals: config.Models.UserGoals,
-			Goals:     config.Models.Goals,
-		},
-		Notificator: config.Notificator,
-	})
-
 	return &Service{
-		Client:      config.VKClient,
-		mapsClient:  config.MapsClient,
-		models:      config.Models,
-		notificator: config.Notificator,
-		registrar:   r,
-		menu:        m,
-		tasks:       t,
-		appraiser:   a,
+		Client:   config.VKClient,
+		models:   config.Models,
+		router:   router.NewRouter(),
+		pool:     config.Pool,
+		mode:     config.Mode,
+		callback: config.Callback,
+	}
+}
+
+// Router get service router to register screen handlers.
+func (s *Service) Router() *router.Router {
+	return s.router
+}
+
+// Listen start receiving messages in configured mode until ctx is done.
+// Handlers get own context, so in-flight messages are finished on shutdown.
+func (s *Service) Listen(ctx context.Context) error {
+	switch s.mode {
+	case "", ModeLongPoll:
+		return s.listenLongPoll(ctx)
+	case ModeCallback:
+		return s.listenCallback(ctx)
 	}
+
+	return fmt.Errorf("%w %q", errUnknownMode, s.mode)
 }
 
-// Listen create stream and start listening.
-func (s *Service) Listen() error {
-	s.listenNotificator()
+// listenLongPoll create stream and start listening until ctx is done.
+func (s *Service) listenLongPoll(ctx context.Context) error {
 	stream, err := s.createStream()
-	ctx := context.Background()
 
 	if err != nil {
 		return err
 	}
 
+	s.wg.Add(1)
+
 	go func() {
+		defer s.wg.Done()
+
 		for {
 			select {
+			case <-ctx.Done():
+				stream.Stop()
+				return
 			case update, ok := <-stream.Updates:
 				if !ok {
 					log.Println("error listen")
+
+					stream, err = s.createStream()
+
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					continue
 				}
 
 				switch msg := update.Data.(type) {
@@ -148,62 +127,16 @@ func (s *Service) Listen() error {
 						continue
 					}
 
-					user, err := s.models.Users.Get(ctx, msg.PeerID)
-
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-
-					var state string
-
-					if user == nil {
-						state, err = s.registrar.Handle(ctx,
-							&Message{
-								NewMessage: msg,
-								user:       user,
-							},
-						)
+					err := s.pool.Submit(msg.PeerID, func() {
+						err := s.handle(context.Background(), msg)
 
 						if err != nil {
 							log.Println(err)
 						}
-					} else {
-						state = user.State
-					}
+					})
 
-					for state != "" {
-						switch state {
-						case "menu":
-							state, err = s.menu.Handle(ctx, &Message{
-								NewMessage: msg,
-								user:       user,
-							})
-
-							if err != nil {
-								log.Println(err)
-							}
-						case "tasks":
-							state, err = s.tasks.Handle(ctx, &Message{
-								NewMessage: msg,
-								user:       user,
-							})
-
-							if err != nil {
-								log.Println(err)
-							}
-						case "rate":
-							state, err = s.appraiser.Handle(ctx, &Message{
-								NewMessage: msg,
-								user:       user,
-							})
-
-							if err != nil {
-								log.Println(err)
-							}
-						default:
-							state = ""
-						}
+					if err != nil {
+						log.Println(err)
 					}
 				}
 			case err, _ := <-stream.Errors:
@@ -219,99 +152,209 @@ func (s *Service) Listen() error {
 	return nil
 }
 
-func (s *Service) listenNotificator() {
-	go func() {
-		for {
-			message := <-s.notificator.Messages
+// Wait wait listening to finish.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
 
-			switch message.Code {
-			case "disapprove":
-				err := s.CallMethod("messages.send", vkSDK.RequestParams{
-					"peer_id":   message.User.ID,
-					"message":   message.Text,
-					"random_id": 0,
-				}, nil)
+func (s *Service) handle(ctx context.Context, msg *lp.NewMessage) error {
+	message := &Message{
+		NewMessage: msg,
+	}
 
-				if err != nil {
-					log.Println(err)
-				}
-			case "next_day":
-				err := s.CallMethod("messages.send", vkSDK.RequestParams{
-					"peer_id":   message.User.ID,
-					"message":   "Не забудьте создать список задач на зватрашний день",
-					"random_id": 0,
-				}, nil)
+	if msg.Attachments["geo"] != "" {
+		location, err := s.getLocation(msg.ID)
 
-				if err != nil {
-					log.Println(err)
-				}
-			case "task_list":
-				uDate, err := message.User.Date(time.Now())
+		if err != nil {
+			log.Println(err)
+		}
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+		message.location = location
+	}
 
-				err = s.tasks.SendGoalList(context.Background(), message.User, *uDate)
+	return s.dispatch(ctx, message)
+}
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+// dispatch route message to screen of user state.
+func (s *Service) dispatch(ctx context.Context, message *Message) error {
+	user, err := s.models.Users.Get(ctx, message.PeerID)
 
-				err = s.CallMethod("messages.send", vkSDK.RequestParams{
-					"peer_id":   message.User.ID,
-					"message":   "Доброе утро! Ваш список задач на сегодня",
-					"random_id": 0,
-				}, nil)
+	if err != nil {
+		return err
+	}
 
-				if err != nil {
-					log.Println(err)
-				}
-			case "mark_tasks":
-				ctx := context.Background()
-				uDate, err := message.User.Date(time.Now())
+	state := services.StateRegister
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+	if user != nil {
+		state = user.State
+	}
 
-				err = s.tasks.MarkGoalList(ctx, message.User, *uDate)
+	message.user = user
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+	err = s.router.Dispatch(ctx, state, message)
 
-				err = s.models.Users.UpdateState(ctx, message.User.ID, "tasks")
+	if err != nil {
+		return err
+	}
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+	return nil
+}
 
-				kb, err := s.models.Keyboards.GetKeyboard(ctx, "vk", "tasks")
+// getLocation get message geo coordinates, long poll
+// only tells that message has geo attachment.
+func (s *Service) getLocation(messageID int64) (*services.Location, error) {
+	var resp struct {
+		Items []struct {
+			Geo *struct {
+				Coordinates services.Location `json:"coordinates"`
+			} `json:"geo"`
+		} `json:"items"`
+	}
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+	err := s.CallMethod("messages.getById", vkSDK.RequestParams{
+		"message_ids": messageID,
+	}, &resp)
 
-				err = s.CallMethod("messages.send", vkSDK.RequestParams{
-					"peer_id":   message.User.ID,
-					"message":   "Доброе вечер! Обновите статусы задач",
-					"random_id": 0,
-					"keyboard":  kb,
-				}, nil)
+	if err != nil {
+		return nil, err
+	}
 
-				if err != nil {
-					log.Println(err)
-				}
-			}
+	if len(resp.Items) == 0 || resp.Items[0].Geo == nil {
+		return nil, nil
+	}
+
+	return &resp.Items[0].Geo.Coordinates, nil
+}
+
+// GetUser get user info from vk.
+func (s *Service) GetUser(userID int64) (*users.User, error) {
+	var userRaws []struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		City      struct {
+			ID    int64  `json:"id"`
+			Title string `json:"title"`
+		} `json:"city"`
+	}
+
+	err := s.CallMethod("users.get", vkSDK.RequestParams{
+		"user_ids": userID,
+		"fields":   "city",
+	}, &userRaws)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userRaws) == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	raw := userRaws[0]
+
+	return &users.User{
+		ID:        userID,
+		FirstName: raw.FirstName,
+		LastName:  raw.LastName,
+		City:      raw.City.Title,
+		Active:    true,
+	}, nil
+}
+
+// SendMessage send message to user.
+func (s *Service) SendMessage(peerID int64, message string) error {
+	err := s.CallMethod("messages.send", vkSDK.RequestParams{
+		"peer_id":   peerID,
+		"message":   message,
+		"random_id": 0,
+	}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendKeyboard send message with keyboard to user.
+func (s *Service) SendKeyboard(peerID int64, message string, keyboard string) error {
+	err := s.CallMethod("messages.send", vkSDK.RequestParams{
+		"peer_id":   peerID,
+		"message":   message,
+		"random_id": 0,
+		"keyboard":  keyboard,
+	}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendList send message with list to user.
+func (s *Service) SendList(peerID int64, message string, list []services.ListItem) error {
+	for _, item := range list {
+		message += fmt.Sprintf("\n - %s", item.GetLabel())
+	}
+
+	return s.SendMessage(peerID, message)
+}
+
+// SendAttachments send message with attachments to user.
+func (s *Service) SendAttachments(peerID int64, message string, attachments []services.Attachment) error {
+	var ids []string
+
+	for _, attachment := range attachments {
+		if attachment.Service != service {
+			continue
 		}
-	}()
+
+		ids = append(ids, attachment.Type+attachment.ID)
+	}
+
+	err := s.CallMethod("messages.send", vkSDK.RequestParams{
+		"peer_id":    peerID,
+		"message":    message,
+		"random_id":  0,
+		"attachment": strings.Join(ids, ","),
+	}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendPhoto upload PNG photo and send it with message to user.
+func (s *Service) SendPhoto(peerID int64, message string, photo []byte) error {
+	id, err := s.uploadPhoto(peerID, photo)
+
+	if err != nil {
+		return err
+	}
+
+	return s.SendAttachments(peerID, message, []services.Attachment{{
+		Service: service,
+		Type:    services.AttachmentPhoto,
+		ID:      id,
+	}})
+}
+
+// SendDocument upload file and send it with message to user.
+func (s *Service) SendDocument(peerID int64, message string, name string, data []byte) error {
+	id, err := s.uploadDocument(peerID, name, data)
+
+	if err != nil {
+		return err
+	}
+
+	return s.SendAttachments(peerID, message, []services.Attachment{{
+		Service: service,
+		Type:    services.AttachmentDoc,
+		ID:      id,
+	}})
 }
 
 func (s *Service) createStream() (*lp.Stream, error) {
@@ -357,7 +400,7 @@ func (m *Message) GetText() string {
 }
 
 // GetPayload get message payload.
-func (m *Message) GetPayload() (s.Payload, error) {
+func (m *Message) GetPayload() (services.Payload, error) {
 	payloadValue := m.Attachments["payload"]
 
 	if payloadValue != "" {
@@ -379,3 +422,33 @@ func (m *Message) GetPayload() (s.Payload, error) {
 func (m *Message) GetUser() *users.User {
 	return m.user
 }
+
+// GetAttachments get message photos and documents.
+func (m *Message) GetAttachments() []services.Attachment {
+	var attachments []services.Attachment
+
+	for i := 1; ; i++ {
+		attachType := m.Attachments[fmt.Sprintf("attach%d_type", i)]
+
+		if attachType == "" {
+			break
+		}
+
+		if attachType != services.AttachmentPhoto && attachType != services.AttachmentDoc {
+			continue
+		}
+
+		attachments = append(attachments, services.Attachment{
+			Service: service,
+			Type:    attachType,
+			ID:      m.Attachments[fmt.Sprintf("attach%d", i)],
+		})
+	}
+
+	return attachments
+}
+
+// GetLocation get message location, nil if it has no location.
+func (m *Message) GetLocation() *services.Location {
+	return m.location
+}
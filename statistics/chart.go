@@ -0,0 +1,72 @@
+package statistics
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+const (
+	chartWidth  = 640
+	chartHeight = 320
+	chartMargin = 20
+)
+
+var (
+	colorBackground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	colorGrid       = color.RGBA{R: 225, G: 225, B: 225, A: 255}
+	colorComplete   = color.RGBA{R: 75, G: 179, B: 75, A: 255}
+	colorFailed     = color.RGBA{R: 230, G: 70, B: 70, A: 255}
+	colorEmpty      = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+)
+
+// Chart draw PNG chart of trend, each week is a bar split
+// into complete and failed parts, weeks without finished goals are grey.
+func Chart(trend []*Period) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: colorBackground}, image.Point{}, draw.Src)
+
+	top := chartMargin
+	bottom := chartHeight - chartMargin
+	height := bottom - top
+
+	for i := 0; i <= 4; i++ {
+		y := bottom - height*i/4
+		fill(img, chartMargin, y, chartWidth-chartMargin, y+1, colorGrid)
+	}
+
+	if len(trend) > 0 {
+		slot := (chartWidth - 2*chartMargin) / len(trend)
+		gap := slot / 5
+
+		for i, p := range trend {
+			left := chartMargin + i*slot + gap
+			right := chartMargin + (i+1)*slot - gap
+
+			if p.Finished() == 0 {
+				fill(img, left, bottom-height/50, right, bottom, colorEmpty)
+				continue
+			}
+
+			split := bottom - int(int64(height)*p.Complete/p.Finished())
+			fill(img, left, top, right, split, colorFailed)
+			fill(img, left, split, right, bottom, colorComplete)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, img)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func fill(img draw.Image, x0, y0, x1, y1 int, c color.Color) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
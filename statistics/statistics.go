@@ -0,0 +1,158 @@
+package statistics
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/scores"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+)
+
+// TrendWeeks number of weeks in completion rate trend.
+const TrendWeeks = 8
+
+type Statistics struct {
+	models ModelsSet
+}
+
+type Config struct {
+	Models ModelsSet
+}
+
+type ModelsSet struct {
+	UserGoals userGoals.Repository
+}
+
+// Counts user goals by status.
+type Counts struct {
+	Complete   int64
+	Failed     int64
+	InProgress int64
+	Soon       int64
+}
+
+// Period user goals of period, total and by goal type.
+type Period struct {
+	Counts
+	Start  time.Time
+	ByType map[int64]*Counts
+}
+
+// Report user statistics for week, month, all time and
+// weekly trend, oldest week first.
+type Report struct {
+	Week  *Period
+	Month *Period
+	All   *Period
+	Trend []*Period
+}
+
+func NewStatistics(config Config) *Statistics {
+	return &Statistics{models: config.Models}
+}
+
+// Report aggregate user goals for periods containing date.
+func (s *Statistics) Report(ctx context.Context, user *users.User, date time.Time) (*Report, error) {
+	uGoals, err := s.models.UserGoals.ListByUser(ctx, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	uDate, err := user.Date(date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	week := scores.PeriodStart(scores.PeriodWeek, *uDate)
+	month := scores.PeriodStart(scores.PeriodMonth, *uDate)
+
+	report := &Report{
+		Month: newPeriod(month),
+		All:   newPeriod(time.Time{}),
+	}
+
+	for i := TrendWeeks - 1; i >= 0; i-- {
+		report.Trend = append(report.Trend, newPeriod(week.AddDate(0, 0, -7*i)))
+	}
+
+	// current week is the last one of trend.
+	report.Week = report.Trend[len(report.Trend)-1]
+
+	for _, uGoal := range uGoals {
+		gDate, err := user.Date(uGoal.From)
+
+		if err != nil {
+			return nil, err
+		}
+
+		day := scores.PeriodStart(scores.PeriodDay, *gDate)
+
+		report.All.add(uGoal)
+
+		if inPeriod(day, month, month.AddDate(0, 1, 0)) {
+			report.Month.add(uGoal)
+		}
+
+		for _, p := range report.Trend {
+			if inPeriod(day, p.Start, p.Start.AddDate(0, 0, 7)) {
+				p.add(uGoal)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Finished get number of finished goals.
+func (c *Counts) Finished() int64 {
+	return c.Complete + c.Failed
+}
+
+// Rate get percent of complete goals among finished.
+func (c *Counts) Rate() int64 {
+	if c.Finished() == 0 {
+		return 0
+	}
+
+	return c.Complete * 100 / c.Finished()
+}
+
+func (c *Counts) add(status string) {
+	switch status {
+	case userGoals.StatusComplete:
+		c.Complete++
+	case userGoals.StatusFailed:
+		c.Failed++
+	case userGoals.StatusInProgress:
+		c.InProgress++
+	case userGoals.StatusSoon:
+		c.Soon++
+	}
+}
+
+func (p *Period) add(uGoal *userGoals.UserGoal) {
+	p.Counts.add(uGoal.Status)
+
+	counts, ok := p.ByType[uGoal.Type]
+
+	if !ok {
+		counts = &Counts{}
+		p.ByType[uGoal.Type] = counts
+	}
+
+	counts.add(uGoal.Status)
+}
+
+func newPeriod(start time.Time) *Period {
+	return &Period{
+		Start:  start,
+		ByType: make(map[int64]*Counts),
+	}
+}
+
+func inPeriod(day time.Time, start time.Time, end time.Time) bool {
+	return !day.Before(start) && day.Before(end)
+}
@@ -0,0 +1,172 @@
+package streaker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Zetkolink/oracle/models/goalTypes"
+	"github.com/Zetkolink/oracle/models/streaks"
+	"github.com/Zetkolink/oracle/models/userGoals"
+	"github.com/Zetkolink/oracle/models/users"
+	"github.com/Zetkolink/oracle/notificator"
+)
+
+// milestones streak lengths user is congratulated on.
+var milestones = map[int64]bool{
+	7:   true,
+	30:  true,
+	100: true,
+}
+
+type Streaker struct {
+	models      ModelsSet
+	notificator *notificator.Notificator
+}
+
+type Config struct {
+	Models      ModelsSet
+	Notificator *notificator.Notificator
+}
+
+type ModelsSet struct {
+	Users     users.Repository
+	GoalTypes goalTypes.Repository
+	UserGoals userGoals.Repository
+	Streaks   streaks.Repository
+}
+
+func NewStreaker(config Config) *Streaker {
+	return &Streaker{
+		models:      config.Models,
+		notificator: config.Notificator,
+	}
+}
+
+// Track recompute streak of user goal type from finished goals
+// and notify user when milestone is reached.
+// Safe to call several times, e.g. when review changes goal status.
+func (s *Streaker) Track(ctx context.Context, uGoal *userGoals.UserGoal) error {
+	user, err := s.models.Users.Get(ctx, uGoal.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	old, err := s.models.Streaks.Get(ctx, user.ID, uGoal.Type)
+
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	streak, err := s.compute(ctx, user, uGoal.Type)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.models.Streaks.Set(ctx, streak)
+
+	if err != nil {
+		return err
+	}
+
+	if !milestones[streak.Current] || (old != nil && old.Current >= streak.Current) {
+		return nil
+	}
+
+	gType, err := s.models.GoalTypes.Get(ctx, uGoal.Type)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.notificator.Send(user, "streak", fmt.Sprintf(
+		"🔥 %d дней подряд - %s! Так держать", streak.Current, gType.Name))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// compute count consecutive completed days of goal type,
+// days without goal or with failed goal break streak.
+func (s *Streaker) compute(ctx context.Context, user *users.User,
+	gType int64) (*streaks.Streak, error) {
+
+	uGoals, err := s.models.UserGoals.ListByUser(ctx, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	type dayStatus struct {
+		date   time.Time
+		status string
+	}
+
+	var days []dayStatus
+
+	for _, uGoal := range uGoals {
+		if uGoal.Type != gType || uGoal.Phase != userGoals.PhaseFinished {
+			continue
+		}
+
+		uDate, err := user.Date(uGoal.From)
+
+		if err != nil {
+			return nil, err
+		}
+
+		days = append(days, dayStatus{date: day(*uDate), status: uGoal.Status})
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].date.Before(days[j].date)
+	})
+
+	streak := &streaks.Streak{
+		UserID: user.ID,
+		Type:   gType,
+	}
+
+	for _, d := range days {
+		date := d.date
+
+		if streak.LastDate != nil {
+			next := streak.LastDate.AddDate(0, 0, 1)
+
+			if streak.Current > 0 && (d.status != userGoals.StatusComplete ||
+				date.After(next)) {
+
+				streak.BrokenAt = &next
+				streak.Current = 0
+			}
+		}
+
+		if d.status == userGoals.StatusComplete {
+			streak.Current++
+		}
+
+		if streak.Current > streak.Best {
+			streak.Best = streak.Current
+		}
+
+		streak.LastDate = &date
+	}
+
+	return streak, nil
+}
+
+// day get calendar date of t as UTC midnight.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}